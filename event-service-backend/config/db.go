@@ -21,17 +21,7 @@ func InitDB() {
 		log.Println("Warning: No .env file found")
 	}
 
-	// Build connection string from environment variables
-	connStr := fmt.Sprintf(
-		"user=%s password=%s dbname=%s host=%s sslmode=%s",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_SSLMODE"),
-	)
-
-	DB, err = sql.Open("postgres", connStr)
+	DB, err = sql.Open("postgres", ConnString())
 	if err != nil {
 		log.Fatal("Error opening database connection:", err)
 	}
@@ -50,6 +40,19 @@ func InitDB() {
 	log.Println("Successfully connected to database!")
 }
 
+// ConnString builds the Postgres connection string from environment
+// variables. Besides the pool, LISTEN connections dial with it.
+func ConnString() string {
+	return fmt.Sprintf(
+		"user=%s password=%s dbname=%s host=%s sslmode=%s",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_SSLMODE"),
+	)
+}
+
 // CloseDB closes the database connection
 func CloseDB() {
 	if DB != nil {
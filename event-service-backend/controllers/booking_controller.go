@@ -9,10 +9,14 @@ import (
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/dharmaseervi/event-service-backend/config"
 	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
 // POST /bookings
+// total_price (optional) is the quoted price. event_id (optional) attaches
+// the booking to one of the user's events; event_date then defaults to the
+// event's first day.
 func CreateBooking(c *gin.Context) {
 	var booking models.Booking
 
@@ -41,6 +45,19 @@ func CreateBooking(c *gin.Context) {
 
 	booking.UserID = localUserID
 
+	// An attached event must be the user's; its first day is the default date
+	if booking.EventID != nil {
+		var startDate time.Time
+		if err := config.DB.QueryRow(`SELECT start_date FROM events WHERE id=$1 AND user_id=$2`,
+			*booking.EventID, localUserID).Scan(&startDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_id"})
+			return
+		}
+		if booking.EventDate.IsZero() {
+			booking.EventDate = startDate
+		}
+	}
+
 	if booking.UserID == 0 || booking.VendorID == 0 || booking.EventDate.IsZero() {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id, vendor_id, and event_date are required"})
 		return
@@ -54,8 +71,8 @@ func CreateBooking(c *gin.Context) {
 
 	// Insert booking
 	query := `
-		INSERT INTO bookings (user_id, vendor_id, event_date, status, notes, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id
+		INSERT INTO bookings (user_id, vendor_id, event_date, status, notes, created_at, updated_at, event_id, total_price)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id
 	`
 	err := config.DB.QueryRow(
 		query,
@@ -66,6 +83,8 @@ func CreateBooking(c *gin.Context) {
 		booking.Notes,
 		booking.CreatedAt,
 		booking.UpdatedAt,
+		booking.EventID,
+		booking.TotalPrice,
 	).Scan(&booking.ID)
 
 	log.Printf("❌ Insert failed: %v", err)
@@ -101,18 +120,27 @@ func GetMyBookings(c *gin.Context) {
 		return
 	}
 
-	const q = `
+	page, err := utils.ParsePage(c, map[string]utils.SortKey{
+		utils.SortNewest: {Expr: "b.created_at", Desc: true, Cast: "timestamptz"},
+	}, utils.SortNewest, "b.id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	args := utils.SQLArgs{localUserID}
+	q := `
 		SELECT
-		  b.id, b.user_id, b.vendor_id, b.event_date, b.status, b.notes, b.created_at, b.updated_at,
+		  b.id, b.user_id, b.vendor_id, b.event_id, b.event_date, b.status, b.total_price::float8, b.notes, b.created_at, b.updated_at,
 		  v.id, v.vendor_id, v.title, v.description, v.category, v.price_range, v.location,
 		  COALESCE(v.photos, ARRAY[]::text[]) AS photos,
-		  v.created_at, v.updated_at
+		  v.created_at, v.updated_at, ` + page.SortValue() + `
 		FROM bookings b
 		LEFT JOIN vendors v ON v.id = b.vendor_id
-		WHERE b.user_id = $1
-		ORDER BY b.created_at DESC
-	`
-	rows, err := config.DB.Query(q, localUserID)
+		WHERE b.user_id = $1 AND ` + page.Where(args.Add) + `
+		ORDER BY ` + page.OrderBy() + `
+		LIMIT ` + args.Add(page.FetchLimit())
+	rows, err := config.DB.Query(q, args...)
 	if err != nil {
 		log.Printf("GetMyBookings query error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bookings"})
@@ -120,19 +148,21 @@ func GetMyBookings(c *gin.Context) {
 	}
 	defer rows.Close()
 
-	var out []BookingWithVendor
+	out := []BookingWithVendor{}
 	count := 0
 	for rows.Next() {
 		var b BookingWithVendor
+		var sortValue string
 		if err := rows.Scan(
-			&b.ID, &b.UserID, &b.VendorID, &b.EventDate, &b.Status, &b.Notes, &b.CreatedAt, &b.UpdatedAt,
+			&b.ID, &b.UserID, &b.VendorID, &b.EventID, &b.EventDate, &b.Status, &b.TotalPrice, &b.Notes, &b.CreatedAt, &b.UpdatedAt,
 			&b.Vendor.ID, &b.Vendor.VendorID, &b.Vendor.Title, &b.Vendor.Description, &b.Vendor.Category,
 			&b.Vendor.PriceRange, &b.Vendor.Location, &b.Vendor.Photos,
-			&b.Vendor.CreatedAt, &b.Vendor.UpdatedAt,
+			&b.Vendor.CreatedAt, &b.Vendor.UpdatedAt, &sortValue,
 		); err != nil {
 			log.Printf("GetMyBookings scan error: %v", err) // ← SEE THIS IN LOGS
 			continue
 		}
+		page.Track(sortValue, b.ID)
 		out = append(out, b)
 		count++
 	}
@@ -143,5 +173,6 @@ func GetMyBookings(c *gin.Context) {
 	}
 
 	log.Printf("GetMyBookings: user %d → %d rows", localUserID, count)
-	c.JSON(http.StatusOK, gin.H{"success": true, "bookings": out})
+	n, next := page.Done()
+	utils.RespondWithPage(c, out[:n], &page, next)
 }
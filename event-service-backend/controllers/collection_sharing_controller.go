@@ -0,0 +1,649 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const collectionInviteTTL = 14 * 24 * time.Hour
+
+// sharedCollectionParam is savedCollectionParam for the sharing endpoints,
+// which make no sense on the default collection.
+func sharedCollectionParam(c *gin.Context, userID int, need string) (collectionAccess, bool) {
+	a, ok := savedCollectionParam(c, userID, need)
+	if ok && a.ID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the default collection cannot be shared"})
+		return a, false
+	}
+	return a, ok
+}
+
+// validMemberRole reports whether role can be given to a collaborator.
+func validMemberRole(role string) bool {
+	return role == collectionViewer || role == collectionEditor
+}
+
+// collectionItemID finds the saved item for :vendor_id in the collection.
+func collectionItemID(c *gin.Context, a collectionAccess) (int, bool) {
+	vendorID, err := strconv.Atoi(c.Param("vendor_id"))
+	if err != nil || vendorID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor_id"})
+		return 0, false
+	}
+	var id int
+	err = config.DB.QueryRow(`
+		SELECT id FROM saved_items
+		WHERE user_id = $1 AND collection_id IS NOT DISTINCT FROM $2 AND vendor_id = $3
+	`, a.OwnerID, a.ID, vendorID).Scan(&id)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "vendor is not in this collection"})
+		return 0, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return 0, false
+	}
+	return id, true
+}
+
+func userDisplayName(userID int) string {
+	var name string
+	config.DB.QueryRow(`SELECT COALESCE(NULLIF(full_name, ''), 'Someone') FROM users WHERE id=$1`, userID).Scan(&name)
+	if name == "" {
+		return "Someone"
+	}
+	return name
+}
+
+// notifyCollaborators pushes a change on a shared collection to its owner
+// and members, except whoever made it.
+func notifyCollaborators(a collectionAccess, actorID int, body string) {
+	if a.ID == nil {
+		return
+	}
+	rows, err := config.DB.Query(`
+		SELECT user_id FROM saved_collections WHERE id = $1 AND user_id <> $2
+		UNION
+		SELECT user_id FROM saved_collection_members WHERE collection_id = $1 AND user_id <> $2
+	`, *a.ID, actorID)
+	if err != nil {
+		log.Printf("notifyCollaborators query error: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			continue
+		}
+		go utils.NotifyUser(userID, a.Name, body, map[string]string{
+			"type":          "collection_update",
+			"collection_id": strconv.Itoa(*a.ID),
+		})
+	}
+}
+
+// POST /saved-vendors/collections/:id/invites
+// body: { "role": "viewer" | "editor", "email": string }
+// Without email the token works as a share link for anyone until it
+// expires; with email only that user can accept it, once. Only the owner
+// can invite.
+func CreateCollectionInvite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := sharedCollectionParam(c, userID, collectionOwner)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Role  string `json:"role"`
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if input.Role == "" {
+		input.Role = collectionViewer
+	}
+	if !validMemberRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be viewer or editor"})
+		return
+	}
+	var email *string
+	if e := strings.ToLower(strings.TrimSpace(input.Email)); e != "" {
+		if !strings.Contains(e, "@") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid email"})
+			return
+		}
+		email = &e
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	inv := models.CollectionInvite{CollectionID: *collection.ID, Token: hex.EncodeToString(b), Email: email, Role: input.Role}
+	err := config.DB.QueryRow(`
+		INSERT INTO saved_collection_invites (collection_id, token, email, role, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, expires_at, created_at
+	`, inv.CollectionID, inv.Token, inv.Email, inv.Role, userID, time.Now().Add(collectionInviteTTL)).Scan(
+		&inv.ID, &inv.ExpiresAt, &inv.CreatedAt)
+	if err != nil {
+		log.Printf("CreateCollectionInvite insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create invite"})
+		return
+	}
+
+	if email != nil {
+		var inviteeID int
+		if config.DB.QueryRow(`SELECT id FROM users WHERE lower(email) = $1`, *email).Scan(&inviteeID) == nil {
+			go utils.NotifyUser(inviteeID, "You're invited to plan together",
+				userDisplayName(userID)+" shared "+collection.Name+" with you", map[string]string{
+					"type":  "collection_invite",
+					"token": inv.Token,
+				})
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invite": inv})
+}
+
+// GET /saved-vendors/collections/:id/invites
+// Invites that can still be accepted. Owner only.
+func GetCollectionInvites(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := sharedCollectionParam(c, userID, collectionOwner)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, collection_id, token, email, role, expires_at, accepted_at, created_at
+		FROM saved_collection_invites
+		WHERE collection_id = $1 AND expires_at > NOW() AND (email IS NULL OR accepted_at IS NULL)
+		ORDER BY created_at DESC
+	`, *collection.ID)
+	if err != nil {
+		log.Printf("GetCollectionInvites query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch invites"})
+		return
+	}
+	defer rows.Close()
+
+	invites := []models.CollectionInvite{}
+	for rows.Next() {
+		var inv models.CollectionInvite
+		if err := rows.Scan(&inv.ID, &inv.CollectionID, &inv.Token, &inv.Email, &inv.Role,
+			&inv.ExpiresAt, &inv.AcceptedAt, &inv.CreatedAt); err != nil {
+			log.Printf("GetCollectionInvites scan error: %v", err)
+			continue
+		}
+		invites = append(invites, inv)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invites": invites})
+}
+
+// DELETE /saved-vendors/collections/:id/invites/:invite_id
+func RevokeCollectionInvite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := sharedCollectionParam(c, userID, collectionOwner)
+	if !ok {
+		return
+	}
+	inviteID, err := strconv.Atoi(c.Param("invite_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invite id"})
+		return
+	}
+
+	res, err := config.DB.Exec(`DELETE FROM saved_collection_invites WHERE id=$1 AND collection_id=$2`,
+		inviteID, *collection.ID)
+	if err != nil {
+		log.Printf("RevokeCollectionInvite error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke invite"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invite not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invite revoked"})
+}
+
+// POST /saved-vendors/invites/:token/accept
+// Joins the collection with the invite's role. Accepting again keeps the
+// stronger of the two roles.
+func AcceptCollectionInvite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var (
+		inv        models.CollectionInvite
+		owner      collectionAccess
+		userEmail  string
+		acceptedBy sql.NullInt64
+	)
+	err := config.DB.QueryRow(`
+		SELECT i.id, i.collection_id, i.email, i.role, i.expires_at, i.accepted_at, i.accepted_by,
+		       sc.user_id, sc.name, COALESCE(lower(u.email), '')
+		FROM saved_collection_invites i
+		JOIN saved_collections sc ON sc.id = i.collection_id
+		JOIN users u ON u.id = $2
+		WHERE i.token = $1
+	`, c.Param("token"), userID).Scan(&inv.ID, &inv.CollectionID, &inv.Email, &inv.Role, &inv.ExpiresAt,
+		&inv.AcceptedAt, &acceptedBy, &owner.OwnerID, &owner.Name, &userEmail)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invite not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("AcceptCollectionInvite lookup error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	owner.ID = &inv.CollectionID
+
+	switch {
+	case time.Now().After(inv.ExpiresAt):
+		c.JSON(http.StatusGone, gin.H{"error": "invite has expired"})
+		return
+	case inv.Email != nil && *inv.Email != userEmail:
+		c.JSON(http.StatusForbidden, gin.H{"error": "this invite is for a different email address"})
+		return
+	case inv.Email != nil && acceptedBy.Valid && int(acceptedBy.Int64) != userID:
+		c.JSON(http.StatusGone, gin.H{"error": "invite has already been used"})
+		return
+	case owner.OwnerID == userID:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "you already own this collection"})
+		return
+	}
+
+	var role string
+	var joined bool
+	err = config.DB.QueryRow(`
+		INSERT INTO saved_collection_members (collection_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (collection_id, user_id) DO UPDATE
+			SET role = CASE WHEN EXCLUDED.role = 'editor' THEN 'editor' ELSE saved_collection_members.role END
+		RETURNING role, xmax = 0
+	`, inv.CollectionID, userID, inv.Role).Scan(&role, &joined)
+	if err != nil {
+		log.Printf("AcceptCollectionInvite insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not join collection"})
+		return
+	}
+	if _, err := config.DB.Exec(`
+		UPDATE saved_collection_invites SET accepted_by = $2, accepted_at = NOW()
+		WHERE id = $1 AND accepted_at IS NULL
+	`, inv.ID, userID); err != nil {
+		log.Printf("AcceptCollectionInvite update error: %v", err)
+	}
+
+	if joined {
+		notifyCollaborators(owner, userID, userDisplayName(userID)+" joined the collection")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"collection_id": inv.CollectionID, "role": role})
+}
+
+// GET /saved-vendors/collections/:id/members
+// The owner first, then collaborators in the order they joined.
+func GetCollectionMembers(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := sharedCollectionParam(c, userID, collectionViewer)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, name, email, role, joined_at FROM (
+			SELECT u.id, COALESCE(u.full_name, '') AS name, COALESCE(u.email, '') AS email, 'owner' AS role, sc.created_at AS joined_at
+			FROM saved_collections sc JOIN users u ON u.id = sc.user_id
+			WHERE sc.id = $1
+			UNION ALL
+			SELECT u.id, COALESCE(u.full_name, ''), COALESCE(u.email, ''), m.role, m.created_at
+			FROM saved_collection_members m JOIN users u ON u.id = m.user_id
+			WHERE m.collection_id = $1
+		) members
+		ORDER BY role = 'owner' DESC, joined_at
+	`, *collection.ID)
+	if err != nil {
+		log.Printf("GetCollectionMembers query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch members"})
+		return
+	}
+	defer rows.Close()
+
+	members := []models.CollectionMember{}
+	for rows.Next() {
+		var m models.CollectionMember
+		if err := rows.Scan(&m.UserID, &m.Name, &m.Email, &m.Role, &m.JoinedAt); err != nil {
+			log.Printf("GetCollectionMembers scan error: %v", err)
+			continue
+		}
+		members = append(members, m)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// PATCH /saved-vendors/collections/:id/members/:user_id
+// body: { "role": "viewer" | "editor" }
+func UpdateCollectionMember(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := sharedCollectionParam(c, userID, collectionOwner)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+
+	var input struct {
+		Role string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || !validMemberRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be viewer or editor"})
+		return
+	}
+
+	res, err := config.DB.Exec(`UPDATE saved_collection_members SET role=$3 WHERE collection_id=$1 AND user_id=$2`,
+		*collection.ID, memberID, input.Role)
+	if err != nil {
+		log.Printf("UpdateCollectionMember error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update member"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member updated"})
+}
+
+// DELETE /saved-vendors/collections/:id/members/:user_id
+// The owner can remove anyone; members can remove themselves to leave.
+func RemoveCollectionMember(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := sharedCollectionParam(c, userID, collectionViewer)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+	if collection.Role != collectionOwner && memberID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can remove other members"})
+		return
+	}
+
+	res, err := config.DB.Exec(`DELETE FROM saved_collection_members WHERE collection_id=$1 AND user_id=$2`,
+		*collection.ID, memberID)
+	if err != nil {
+		log.Printf("RemoveCollectionMember error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not remove member"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// POST /saved-vendors/collections/:id/vendors
+// body: { "vendor_id": number, "notes": string, "tags": [string] }
+// Adds a vendor to the collection on the owner's behalf; needs editor.
+func AddCollectionVendor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := savedCollectionParam(c, userID, collectionEditor)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		VendorID int      `json:"vendor_id"`
+		Notes    *string  `json:"notes"`
+		Tags     []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.VendorID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vendor_id is required"})
+		return
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var title string
+	if err := config.DB.QueryRow(`SELECT title FROM vendors WHERE id=$1`, input.VendorID).Scan(&title); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor_id"})
+		return
+	}
+
+	// The owner can only save a vendor once; if it sits in another of their
+	// collections it is left there rather than moved by a collaborator.
+	var it models.SavedVendorItem
+	err = config.DB.QueryRow(`
+		INSERT INTO saved_items (user_id, vendor_id, collection_id, notes, tags, added_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, vendor_id) DO NOTHING
+		RETURNING id, COALESCE(collection_id, 0), COALESCE(notes, ''), COALESCE(tags, '{}'), position, added_by, created_at
+	`, collection.OwnerID, input.VendorID, collection.ID, input.Notes, tags, userID).Scan(
+		&it.ID, &it.CollectionID, &it.Notes, &it.Tags, &it.Position, &it.AddedBy, &it.SavedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "vendor is already saved in one of the owner's collections"})
+		return
+	}
+	if err != nil {
+		log.Printf("AddCollectionVendor insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add vendor"})
+		return
+	}
+
+	notifyCollaborators(collection, userID, userDisplayName(userID)+" added "+title)
+	c.JSON(http.StatusCreated, gin.H{"saved_vendor": it})
+}
+
+// DELETE /saved-vendors/collections/:id/vendors/:vendor_id
+// Removes the vendor from the owner's saved list entirely; needs editor.
+func RemoveCollectionVendor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := savedCollectionParam(c, userID, collectionEditor)
+	if !ok {
+		return
+	}
+	itemID, ok := collectionItemID(c, collection)
+	if !ok {
+		return
+	}
+
+	var title string
+	err := config.DB.QueryRow(`
+		DELETE FROM saved_items si USING vendors v
+		WHERE si.id = $1 AND v.id = si.vendor_id
+		RETURNING v.title
+	`, itemID).Scan(&title)
+	if err != nil {
+		log.Printf("RemoveCollectionVendor error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not remove vendor"})
+		return
+	}
+
+	notifyCollaborators(collection, userID, userDisplayName(userID)+" removed "+title)
+	c.JSON(http.StatusOK, gin.H{"message": "vendor removed"})
+}
+
+// GET /saved-vendors/collections/:id/vendors/:vendor_id/comments
+// Oldest first, like a chat thread.
+func GetSavedVendorComments(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := savedCollectionParam(c, userID, collectionViewer)
+	if !ok {
+		return
+	}
+	itemID, ok := collectionItemID(c, collection)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT cm.id, COALESCE(cm.user_id, 0), COALESCE(u.full_name, ''), cm.body, cm.created_at
+		FROM saved_item_comments cm
+		LEFT JOIN users u ON u.id = cm.user_id
+		WHERE cm.saved_item_id = $1
+		ORDER BY cm.created_at, cm.id
+	`, itemID)
+	if err != nil {
+		log.Printf("GetSavedVendorComments query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch comments"})
+		return
+	}
+	defer rows.Close()
+
+	comments := []models.SavedVendorComment{}
+	for rows.Next() {
+		var cm models.SavedVendorComment
+		if err := rows.Scan(&cm.ID, &cm.UserID, &cm.UserName, &cm.Body, &cm.CreatedAt); err != nil {
+			log.Printf("GetSavedVendorComments scan error: %v", err)
+			continue
+		}
+		comments = append(comments, cm)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+// POST /saved-vendors/collections/:id/vendors/:vendor_id/comments
+// body: { "body": string }
+func AddSavedVendorComment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := savedCollectionParam(c, userID, collectionViewer)
+	if !ok {
+		return
+	}
+	itemID, ok := collectionItemID(c, collection)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Body string `json:"body"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body is required"})
+		return
+	}
+	if len(input.Body) > 2000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment is too long"})
+		return
+	}
+
+	cm := models.SavedVendorComment{UserID: userID, UserName: userDisplayName(userID), Body: strings.TrimSpace(input.Body)}
+	err := config.DB.QueryRow(`
+		INSERT INTO saved_item_comments (saved_item_id, user_id, body) VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, itemID, userID, cm.Body).Scan(&cm.ID, &cm.CreatedAt)
+	if err != nil {
+		log.Printf("AddSavedVendorComment insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add comment"})
+		return
+	}
+
+	notifyCollaborators(collection, userID, cm.UserName+" commented: "+cm.Body)
+	c.JSON(http.StatusCreated, gin.H{"comment": cm})
+}
+
+// POST /saved-vendors/collections/:id/vendors/:vendor_id/vote
+// A thumbs-up; voting twice is a no-op.
+func VoteSavedVendor(c *gin.Context) {
+	setSavedVendorVote(c, true)
+}
+
+// DELETE /saved-vendors/collections/:id/vendors/:vendor_id/vote
+func UnvoteSavedVendor(c *gin.Context) {
+	setSavedVendorVote(c, false)
+}
+
+func setSavedVendorVote(c *gin.Context, up bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := savedCollectionParam(c, userID, collectionViewer)
+	if !ok {
+		return
+	}
+	itemID, ok := collectionItemID(c, collection)
+	if !ok {
+		return
+	}
+
+	query := `DELETE FROM saved_item_votes WHERE saved_item_id=$1 AND user_id=$2`
+	if up {
+		query = `INSERT INTO saved_item_votes (saved_item_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+	if _, err := config.DB.Exec(query, itemID, userID); err != nil {
+		log.Printf("setSavedVendorVote error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save vote"})
+		return
+	}
+
+	var votes int
+	config.DB.QueryRow(`SELECT COUNT(*) FROM saved_item_votes WHERE saved_item_id=$1`, itemID).Scan(&votes)
+	c.JSON(http.StatusOK, gin.H{"votes": votes, "voted": up})
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+)
+
+// readCSVHeader reads the header row of an uploaded CSV and maps each
+// lowercased column name to its index. Files saved as UTF-8 from Excel
+// start with a byte order mark, which would otherwise stick to the first
+// column name.
+func readCSVHeader(cr *csv.Reader) (map[string]int, error) {
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.New("could not read CSV header")
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	return cols, nil
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVHeaderStripsBOM(t *testing.T) {
+	for name, file := range map[string]string{
+		"plain": "Name, Email,Phone\nAsha,asha@example.com,\n",
+		"bom":   "\ufeffName, Email,Phone\nAsha,asha@example.com,\n",
+	} {
+		cols, err := readCSVHeader(csv.NewReader(strings.NewReader(file)))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for col, want := range map[string]int{"name": 0, "email": 1, "phone": 2} {
+			if i, ok := cols[col]; !ok || i != want {
+				t.Errorf("%s: column %q at %d (found %v), want %d", name, col, i, ok, want)
+			}
+		}
+	}
+}
+
+func TestParseVendorImportCSVWithBOM(t *testing.T) {
+	rows, err := parseVendorImportCSV(strings.NewReader("\ufefftitle,vendor_id\nLens Studio,7\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Data.Title != "Lens Studio" {
+		t.Errorf("got %+v, want one row titled Lens Studio", rows)
+	}
+}
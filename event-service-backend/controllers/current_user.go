@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID maps the Clerk session on the request to the local users.id.
+// It writes the error response itself and returns ok=false when the caller
+// should stop.
+func currentUserID(c *gin.Context) (int, bool) {
+	// RequireRoleMiddleware already resolved the user
+	if id, exists := c.Get("user_id"); exists {
+		return id.(int), true
+	}
+
+	claims, ok := clerk.SessionClaimsFromContext(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, false
+	}
+
+	var localUserID int
+	if err := config.DB.QueryRow(`SELECT id FROM users WHERE clerk_id=$1`, claims.Subject).Scan(&localUserID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			return 0, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return 0, false
+	}
+
+	return localUserID, true
+}
@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// budgetCategory normalizes a budget or expense category: a vendor
+// category, or "other".
+func budgetCategory(raw string) (string, bool) {
+	category := strings.ToLower(strings.TrimSpace(raw))
+	return category, category == "other" || slices.Contains(models.VendorCategories, category)
+}
+
+// GET /events/:id/budget
+// Spend against budget per category. Bookings count under their vendor's
+// category at their total_price; bookings without a price count as 0.
+func GetEventBudget(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	report, err := eventBudget(e)
+	if err != nil {
+		log.Printf("GetEventBudget error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"budget": report})
+}
+
+func eventBudget(e models.Event) (models.EventBudget, error) {
+	report := models.EventBudget{EventID: e.ID, Categories: []models.BudgetLine{}}
+
+	rows, err := config.DB.Query(`
+		SELECT category, 'budget', amount::float8 FROM event_budgets WHERE event_id = $1
+		UNION ALL
+		SELECT COALESCE(v.category, 'other'),
+		       CASE WHEN b.status = 'pending' THEN 'quoted' ELSE 'confirmed' END,
+		       SUM(COALESCE(b.total_price, 0))::float8
+		FROM bookings b LEFT JOIN vendors v ON v.id = b.vendor_id
+		WHERE b.event_id = $1 AND b.status IN ('pending', 'confirmed', 'completed')
+		GROUP BY 1, 2
+		UNION ALL
+		SELECT category, 'expenses', SUM(amount)::float8 FROM event_expenses WHERE event_id = $1 GROUP BY 1
+	`, e.ID)
+	if err != nil {
+		return report, err
+	}
+	defer rows.Close()
+
+	lines := map[string]*models.BudgetLine{}
+	for rows.Next() {
+		var category, kind string
+		var amount float64
+		if err := rows.Scan(&category, &kind, &amount); err != nil {
+			return report, err
+		}
+		line := lines[category]
+		if line == nil {
+			line = &models.BudgetLine{Category: category}
+			lines[category] = line
+		}
+		switch kind {
+		case "budget":
+			line.Budget = &amount
+		case "quoted":
+			line.Quoted += amount
+		case "confirmed":
+			line.Confirmed += amount
+		case "expenses":
+			line.Expenses += amount
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return report, err
+	}
+
+	var categoryBudgets *float64
+	for _, line := range lines {
+		if line.Budget != nil {
+			sum := *line.Budget
+			if categoryBudgets != nil {
+				sum += *categoryBudgets
+			}
+			categoryBudgets = &sum
+		}
+		report.Total.Quoted += line.Quoted
+		report.Total.Confirmed += line.Confirmed
+		report.Total.Expenses += line.Expenses
+		settleBudgetLine(line)
+		report.Categories = append(report.Categories, *line)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool { return report.Categories[i].Category < report.Categories[j].Category })
+
+	// the event's own budget wins over the sum of category budgets
+	report.Total.Budget = e.Budget
+	if report.Total.Budget == nil {
+		report.Total.Budget = categoryBudgets
+	}
+	settleBudgetLine(&report.Total)
+	return report, nil
+}
+
+func settleBudgetLine(line *models.BudgetLine) {
+	line.Committed = line.Quoted + line.Confirmed + line.Expenses
+	if line.Budget == nil {
+		return
+	}
+	remaining := *line.Budget - line.Committed
+	line.Remaining = &remaining
+	if remaining < 0 {
+		line.Overspend = -remaining
+	}
+}
+
+// PUT /events/:id/budget
+// body: { "categories": { "venue": 200000, "catering": 150000 } }
+// Replaces every category budget; leave a category out to drop its budget.
+func SetEventBudget(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Categories map[string]float64 `json:"categories"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	var categories []string
+	var amounts []float64
+	for raw, amount := range input.Categories {
+		category, ok := budgetCategory(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown category: " + raw})
+			return
+		}
+		if amount < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "budget for " + category + " must not be negative"})
+			return
+		}
+		categories = append(categories, category)
+		amounts = append(amounts, amount)
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM event_budgets WHERE event_id=$1`, e.ID); err != nil {
+		log.Printf("SetEventBudget delete error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save budget"})
+		return
+	}
+	for i, category := range categories {
+		if _, err := tx.Exec(`
+			INSERT INTO event_budgets (event_id, category, amount) VALUES ($1, $2, $3)
+			ON CONFLICT (event_id, category) DO UPDATE SET amount = EXCLUDED.amount, updated_at = NOW()
+		`, e.ID, category, amounts[i]); err != nil {
+			log.Printf("SetEventBudget insert error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save budget"})
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save budget"})
+		return
+	}
+
+	report, err := eventBudget(e)
+	if err != nil {
+		log.Printf("SetEventBudget report error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"budget": report})
+}
+
+// GET /events/:id/expenses
+// Newest first.
+func GetEventExpenses(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, event_id, category, description, amount::float8, spent_on::text, created_at
+		FROM event_expenses
+		WHERE event_id = $1
+		ORDER BY spent_on DESC, id DESC
+	`, e.ID)
+	if err != nil {
+		log.Printf("GetEventExpenses query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch expenses"})
+		return
+	}
+	defer rows.Close()
+
+	expenses := []models.EventExpense{}
+	for rows.Next() {
+		var x models.EventExpense
+		if err := rows.Scan(&x.ID, &x.EventID, &x.Category, &x.Description, &x.Amount, &x.SpentOn, &x.CreatedAt); err != nil {
+			log.Printf("GetEventExpenses scan error: %v", err)
+			continue
+		}
+		expenses = append(expenses, x)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"expenses": expenses})
+}
+
+// POST /events/:id/expenses
+// body: { "category": "decor", "description": "Extra flowers", "amount": 4500, "spent_on": "2025-11-20" }
+// spent_on defaults to today.
+func AddEventExpense(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Category    string  `json:"category"`
+		Description string  `json:"description"`
+		Amount      float64 `json:"amount"`
+		SpentOn     string  `json:"spent_on"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	x := models.EventExpense{EventID: e.ID, Description: strings.TrimSpace(input.Description), Amount: input.Amount}
+	if x.Category, ok = budgetCategory(input.Category); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown category: " + input.Category})
+		return
+	}
+	if x.Description == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "description is required"})
+		return
+	}
+	if x.Amount <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be positive"})
+		return
+	}
+	x.SpentOn = strings.TrimSpace(input.SpentOn)
+	if x.SpentOn == "" {
+		x.SpentOn = time.Now().Format(time.DateOnly)
+	} else if _, err := time.Parse(time.DateOnly, x.SpentOn); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "spent_on must be a date like 2025-12-31"})
+		return
+	}
+
+	err := config.DB.QueryRow(`
+		INSERT INTO event_expenses (event_id, category, description, amount, spent_on)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, x.EventID, x.Category, x.Description, x.Amount, x.SpentOn).Scan(&x.ID, &x.CreatedAt)
+	if err != nil {
+		log.Printf("AddEventExpense insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add expense"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"expense": x})
+}
+
+// DELETE /events/:id/expenses/:expense_id
+func DeleteEventExpense(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+	expenseID, err := strconv.Atoi(c.Param("expense_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense id"})
+		return
+	}
+
+	res, err := config.DB.Exec(`DELETE FROM event_expenses WHERE id=$1 AND event_id=$2`, expenseID, e.ID)
+	if err != nil {
+		log.Printf("DeleteEventExpense error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete expense"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "expense not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "expense deleted"})
+}
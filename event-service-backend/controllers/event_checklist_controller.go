@@ -0,0 +1,440 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// checklistRemindEvery is how often an overdue task is reminded about again
+const checklistRemindEvery = 7 * 24 * time.Hour
+
+const eventTaskSelect = `
+	SELECT id, event_id, template_id, title, category, due_date::text, done, done_at, booking_id,
+	       NOT done AND due_date < CURRENT_DATE, created_at, updated_at
+	FROM event_tasks
+`
+
+func scanEventTask(scanner interface{ Scan(...any) error }, t *models.EventTask) error {
+	return scanner.Scan(&t.ID, &t.EventID, &t.TemplateID, &t.Title, &t.Category, &t.DueDate, &t.Done, &t.DoneAt,
+		&t.BookingID, &t.Overdue, &t.CreatedAt, &t.UpdatedAt)
+}
+
+// templateDueDate is when a template task falls due: offset_days before the
+// start, but never in the past unless the event itself is.
+const templateDueDate = `GREATEST(e.start_date - tpl.offset_days, LEAST(CURRENT_DATE, e.start_date))`
+
+// instantiateChecklist adds the templates for the event's type that it does
+// not have yet. Tasks whose category already has a confirmed booking start
+// out done.
+func instantiateChecklist(eventID int) (int64, error) {
+	res, err := config.DB.Exec(`
+		INSERT INTO event_tasks (event_id, template_id, title, category, due_date, done, done_at, booking_id)
+		SELECT e.id, tpl.id, tpl.title, tpl.category, `+templateDueDate+`,
+		       b.id IS NOT NULL, CASE WHEN b.id IS NOT NULL THEN NOW() END, b.id
+		FROM events e
+		JOIN checklist_templates tpl ON tpl.event_type = e.type
+		LEFT JOIN LATERAL (
+			SELECT bk.id FROM bookings bk JOIN vendors v ON v.id = bk.vendor_id
+			WHERE bk.event_id = e.id AND bk.status = 'confirmed' AND v.category = tpl.category
+			ORDER BY bk.id LIMIT 1
+		) b ON TRUE
+		WHERE e.id = $1
+		ON CONFLICT (event_id, template_id) DO NOTHING
+	`, eventID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// clearChecklistType deletes the open tasks that came from another event
+// type's templates, after the event changed type. Done tasks stay as a
+// record of what was already handled.
+func clearChecklistType(eventID int, eventType string) error {
+	_, err := config.DB.Exec(`
+		DELETE FROM event_tasks t
+		USING checklist_templates tpl
+		WHERE tpl.id = t.template_id AND t.event_id = $1 AND tpl.event_type = $2 AND NOT t.done
+	`, eventID, eventType)
+	return err
+}
+
+// rescheduleChecklist moves the open template tasks after the event date
+// changed. Custom tasks keep the date the user gave them.
+func rescheduleChecklist(eventID int) error {
+	_, err := config.DB.Exec(`
+		UPDATE event_tasks t SET due_date = `+templateDueDate+`, updated_at = NOW()
+		FROM events e, checklist_templates tpl
+		WHERE e.id = t.event_id AND tpl.id = t.template_id AND t.event_id = $1 AND NOT t.done
+	`, eventID)
+	return err
+}
+
+// eventTaskParam loads :task_id on the event.
+func eventTaskParam(c *gin.Context, e models.Event) (models.EventTask, bool) {
+	var t models.EventTask
+	taskID, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return t, false
+	}
+	err = scanEventTask(config.DB.QueryRow(eventTaskSelect+` WHERE id=$1 AND event_id=$2`, taskID, e.ID), &t)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		return t, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return t, false
+	}
+	return t, true
+}
+
+// GET /events/:id/checklist
+// Open tasks by due date, then done ones.
+func GetEventChecklist(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(eventTaskSelect+` WHERE event_id=$1 ORDER BY done, due_date, id`, e.ID)
+	if err != nil {
+		log.Printf("GetEventChecklist query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch checklist"})
+		return
+	}
+	defer rows.Close()
+
+	tasks := []models.EventTask{}
+	done := 0
+	for rows.Next() {
+		var t models.EventTask
+		if err := scanEventTask(rows, &t); err != nil {
+			log.Printf("GetEventChecklist scan error: %v", err)
+			continue
+		}
+		if t.Done {
+			done++
+		}
+		tasks = append(tasks, t)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks, "done": done, "total": len(tasks)})
+}
+
+// POST /events/:id/checklist
+// Adds any template tasks the event is missing, e.g. after its type changed.
+// Events get their checklist when they are created, so this is rarely needed.
+func ApplyEventChecklist(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	added, err := instantiateChecklist(e.ID)
+	if err != nil {
+		log.Printf("ApplyEventChecklist error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not apply checklist"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"added": added})
+}
+
+// POST /events/:id/tasks
+// body: { "title": "Buy return gifts", "due_date": "2025-12-20", "category": "decor" }
+// category is optional; with one, a confirmed booking in it ticks the task off.
+func AddEventTask(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Title    string `json:"title"`
+		DueDate  string `json:"due_date"`
+		Category string `json:"category"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
+		return
+	}
+	if input.DueDate == "" {
+		input.DueDate = e.StartDate
+	}
+	if _, err := time.Parse(time.DateOnly, input.DueDate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "due_date must be a date like 2025-12-31"})
+		return
+	}
+	var category *string
+	if input.Category != "" {
+		if !slices.Contains(models.VendorCategories, input.Category) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown category: " + input.Category})
+			return
+		}
+		category = &input.Category
+	}
+
+	var t models.EventTask
+	err := scanEventTask(config.DB.QueryRow(`
+		INSERT INTO event_tasks (event_id, title, category, due_date) VALUES ($1, $2, $3, $4)
+		RETURNING id, event_id, template_id, title, category, due_date::text, done, done_at, booking_id,
+		          NOT done AND due_date < CURRENT_DATE, created_at, updated_at
+	`, e.ID, strings.TrimSpace(input.Title), category, input.DueDate), &t)
+	if err != nil {
+		log.Printf("AddEventTask insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add task"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"task": t})
+}
+
+// PATCH /events/:id/tasks/:task_id
+// body: any of { "title", "due_date", "done" }
+func UpdateEventTask(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+	t, ok := eventTaskParam(c, e)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Title   *string `json:"title"`
+		DueDate *string `json:"due_date"`
+		Done    *bool   `json:"done"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if input.Title != nil {
+		if *input.Title = strings.TrimSpace(*input.Title); *input.Title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "title must not be empty"})
+			return
+		}
+	}
+	if input.DueDate != nil {
+		if _, err := time.Parse(time.DateOnly, *input.DueDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "due_date must be a date like 2025-12-31"})
+			return
+		}
+	}
+
+	err := scanEventTask(config.DB.QueryRow(`
+		UPDATE event_tasks SET
+			title = COALESCE($2, title),
+			due_date = COALESCE($3::date, due_date),
+			done = COALESCE($4, done),
+			done_at = CASE WHEN $4 IS NULL THEN done_at WHEN $4 THEN COALESCE(done_at, NOW()) END,
+			booking_id = CASE WHEN $4 = FALSE THEN NULL ELSE booking_id END,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, event_id, template_id, title, category, due_date::text, done, done_at, booking_id,
+		          NOT done AND due_date < CURRENT_DATE, created_at, updated_at
+	`, t.ID, input.Title, input.DueDate, input.Done), &t)
+	if err != nil {
+		log.Printf("UpdateEventTask error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"task": t})
+}
+
+// DELETE /events/:id/tasks/:task_id
+// Template tasks can be deleted too; POST /events/:id/checklist brings them back.
+func DeleteEventTask(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+	t, ok := eventTaskParam(c, e)
+	if !ok {
+		return
+	}
+
+	if _, err := config.DB.Exec(`DELETE FROM event_tasks WHERE id=$1`, t.ID); err != nil {
+		log.Printf("DeleteEventTask error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "task deleted"})
+}
+
+// GET /admin/checklist-templates?event_type=
+func GetChecklistTemplates(c *gin.Context) {
+	var args utils.SQLArgs
+	where := "TRUE"
+	if eventType := c.Query("event_type"); eventType != "" {
+		where = "event_type = " + args.Add(eventType)
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, event_type, title, category, offset_days, created_at
+		FROM checklist_templates
+		WHERE `+where+`
+		ORDER BY event_type, offset_days DESC, id
+	`, args...)
+	if err != nil {
+		log.Printf("GetChecklistTemplates query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch templates"})
+		return
+	}
+	defer rows.Close()
+
+	templates := []models.ChecklistTemplate{}
+	for rows.Next() {
+		var t models.ChecklistTemplate
+		if err := rows.Scan(&t.ID, &t.EventType, &t.Title, &t.Category, &t.OffsetDays, &t.CreatedAt); err != nil {
+			log.Printf("GetChecklistTemplates scan error: %v", err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// POST /admin/checklist-templates
+// body: { "event_type": "wedding", "title": "Book mehndi artist", "category": "decor", "offset_days": 30 }
+// New templates reach existing events through POST /events/:id/checklist.
+func CreateChecklistTemplate(c *gin.Context) {
+	var input struct {
+		EventType  string  `json:"event_type"`
+		Title      string  `json:"title"`
+		Category   *string `json:"category"`
+		OffsetDays int     `json:"offset_days"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	t := models.ChecklistTemplate{EventType: input.EventType, Title: strings.TrimSpace(input.Title),
+		Category: input.Category, OffsetDays: input.OffsetDays}
+	switch {
+	case !slices.Contains(models.EventTypes, t.EventType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event_type must be one of " + strings.Join(models.EventTypes, ", ")})
+		return
+	case t.Title == "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
+		return
+	case t.Category != nil && !slices.Contains(models.VendorCategories, *t.Category):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown category: " + *t.Category})
+		return
+	case t.OffsetDays < 0:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset_days must not be negative"})
+		return
+	}
+
+	err := config.DB.QueryRow(`
+		INSERT INTO checklist_templates (event_type, title, category, offset_days) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, t.EventType, t.Title, t.Category, t.OffsetDays).Scan(&t.ID, &t.CreatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		c.JSON(http.StatusConflict, gin.H{"error": "a template with this title already exists for the event type"})
+		return
+	}
+	if err != nil {
+		log.Printf("CreateChecklistTemplate insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"template": t})
+}
+
+// DELETE /admin/checklist-templates/:id
+// Tasks already created from it stay as custom tasks.
+func DeleteChecklistTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	res, err := config.DB.Exec(`DELETE FROM checklist_templates WHERE id=$1`, id)
+	if err != nil {
+		log.Printf("DeleteChecklistTemplate error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete template"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "template deleted"})
+}
+
+// RunChecklistReminders pushes each planner one reminder per event listing
+// its overdue tasks. A task is reminded about again after a week if it is
+// still open. Cancelled and completed events are left alone.
+func RunChecklistReminders(ctx context.Context) error {
+	rows, err := config.DB.QueryContext(ctx, `
+		WITH due AS (
+			UPDATE event_tasks t SET reminded_at = NOW()
+			FROM events e
+			WHERE e.id = t.event_id AND e.status = 'planning' AND NOT t.done AND t.due_date < CURRENT_DATE
+			  AND (t.reminded_at IS NULL OR t.reminded_at < $1)
+			RETURNING t.event_id, e.user_id, e.name, t.title
+		)
+		SELECT event_id, user_id, name, COUNT(*), MIN(title) FROM due GROUP BY 1, 2, 3
+	`, time.Now().Add(-checklistRemindEvery))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventID, userID, count int
+		var name, title string
+		if err := rows.Scan(&eventID, &userID, &name, &count, &title); err != nil {
+			return err
+		}
+		body := "Overdue: " + title
+		if count > 1 {
+			body = plural(count, "overdue task")
+		}
+		go utils.NotifyUser(userID, name, body, map[string]string{
+			"type":     "checklist_reminder",
+			"event_id": strconv.Itoa(eventID),
+		})
+	}
+	return rows.Err()
+}
@@ -0,0 +1,425 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const eventColumns = `
+	id, user_id, planner_id, name, type, start_date::text, end_date::text, COALESCE(city, ''), guest_count,
+	budget::float8, collection_id, status, created_at, updated_at
+`
+
+const eventSelect = `SELECT ` + eventColumns + ` FROM events`
+
+// eventAccess lets the owner and the owner's active planner in; $2 is the caller.
+const eventAccess = `(user_id = $2 OR planner_id = $2 AND EXISTS (
+	SELECT 1 FROM planner_clients pc
+	WHERE pc.planner_id = $2 AND pc.client_id = events.user_id AND pc.status = 'active'
+))`
+
+func scanEvent(scanner interface{ Scan(...any) error }, e *models.Event) error {
+	return scanner.Scan(&e.ID, &e.UserID, &e.PlannerID, &e.Name, &e.Type, &e.StartDate, &e.EndDate, &e.City,
+		&e.GuestCount, &e.Budget, &e.CollectionID, &e.Status, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// eventInput is the body of POST and PATCH /events. PATCH leaves nil
+// fields alone.
+type eventInput struct {
+	Name         *string  `json:"name"`
+	Type         *string  `json:"type"`
+	StartDate    *string  `json:"start_date"`
+	EndDate      *string  `json:"end_date"`
+	City         *string  `json:"city"`
+	GuestCount   *int     `json:"guest_count"`
+	Budget       *float64 `json:"budget"`
+	CollectionID *int     `json:"collection_id"` // 0 unlinks the shortlist
+	PlannerID    *int     `json:"planner_id"`    // owner only; 0 removes the planner
+	Status       *string  `json:"status"`
+}
+
+// shiftEndDate moves end by as many days as the start moved. end is
+// returned unchanged when any date does not parse; apply reports those.
+func shiftEndDate(oldStart, end, newStart string) string {
+	from, err1 := time.Parse(time.DateOnly, oldStart)
+	to, err2 := time.Parse(time.DateOnly, newStart)
+	last, err3 := time.Parse(time.DateOnly, end)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return end
+	}
+	return last.Add(to.Sub(from)).Format(time.DateOnly)
+}
+
+// apply validates the input on top of e. Errors are safe to show.
+func (in eventInput) apply(e *models.Event) error {
+	if in.Name != nil {
+		if e.Name = strings.TrimSpace(*in.Name); e.Name == "" {
+			return errors.New("name must not be empty")
+		}
+	}
+	if in.Type != nil {
+		if e.Type = strings.ToLower(strings.TrimSpace(*in.Type)); !slices.Contains(models.EventTypes, e.Type) {
+			return errors.New("type must be one of " + strings.Join(models.EventTypes, ", "))
+		}
+	}
+	if in.Status != nil {
+		if e.Status = *in.Status; !slices.Contains(models.EventStatuses, e.Status) {
+			return errors.New("status must be one of " + strings.Join(models.EventStatuses, ", "))
+		}
+	}
+	if in.StartDate != nil {
+		oldStart, oldEnd := e.StartDate, e.EndDate
+		e.StartDate = strings.TrimSpace(*in.StartDate)
+		// a new start date alone moves the whole event, keeping its length
+		if in.EndDate == nil {
+			e.EndDate = shiftEndDate(oldStart, oldEnd, e.StartDate)
+		}
+	}
+	if in.EndDate != nil {
+		e.EndDate = strings.TrimSpace(*in.EndDate)
+	}
+	if e.EndDate == "" {
+		e.EndDate = e.StartDate
+	}
+	start, err := time.Parse(time.DateOnly, e.StartDate)
+	if err != nil {
+		return errors.New("start_date must be a date like 2025-12-31")
+	}
+	end, err := time.Parse(time.DateOnly, e.EndDate)
+	if err != nil {
+		return errors.New("end_date must be a date like 2025-12-31")
+	}
+	if end.Before(start) {
+		return errors.New("end_date must not be before start_date")
+	}
+	if in.City != nil {
+		e.City = strings.TrimSpace(*in.City)
+	}
+	if in.GuestCount != nil {
+		if *in.GuestCount < 0 {
+			return errors.New("guest_count must not be negative")
+		}
+		e.GuestCount = in.GuestCount
+	}
+	if in.Budget != nil {
+		if *in.Budget < 0 {
+			return errors.New("budget must not be negative")
+		}
+		e.Budget = in.Budget
+	}
+	return nil
+}
+
+// ownedEvent loads :id for its owner or the owner's planner and marks the
+// request for the audit trail. It writes the error response itself and
+// returns ok=false when the caller should stop.
+func ownedEvent(c *gin.Context, userID int) (models.Event, bool) {
+	var e models.Event
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return e, false
+	}
+	err = scanEvent(config.DB.QueryRow(eventSelect+` WHERE id=$1 AND `+eventAccess, id, userID), &e)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		return e, false
+	}
+	if err != nil {
+		log.Printf("ownedEvent error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return e, false
+	}
+	c.Set("audit_actor_id", userID)
+	c.Set("audit_client_id", e.UserID)
+	c.Set("audit_event_id", e.ID)
+	return e, true
+}
+
+// daysUntil counts calendar days from today to a YYYY-MM-DD date.
+func daysUntil(date string) int {
+	d, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return 0
+	}
+	today, _ := time.Parse(time.DateOnly, time.Now().Format(time.DateOnly))
+	return int(d.Sub(today).Hours() / 24)
+}
+
+// POST /events
+// body: { "name", "type", "start_date", "end_date", "city", "guest_count", "budget", "collection_id" }
+// name, type and start_date are required; end_date defaults to start_date.
+// The event starts with the checklist for its type.
+func CreateEvent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	createEvent(c, userID, nil)
+}
+
+// createEvent creates an event owned by ownerID, optionally managed by a
+// planner acting for them.
+func createEvent(c *gin.Context, ownerID int, plannerID *int) {
+	var input eventInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if input.Name == nil || input.Type == nil || input.StartDate == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name, type and start_date are required"})
+		return
+	}
+
+	e := models.Event{UserID: ownerID, PlannerID: plannerID, Status: "planning"}
+	if err := input.apply(&e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.CollectionID != nil {
+		var ok bool
+		if e.CollectionID, ok = ownedCollection(c, ownerID, *input.CollectionID); !ok {
+			return
+		}
+	}
+
+	err := scanEvent(config.DB.QueryRow(`
+		INSERT INTO events (user_id, planner_id, name, type, start_date, end_date, city, guest_count, budget,
+		                    collection_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11)
+		RETURNING `+eventColumns,
+		e.UserID, e.PlannerID, e.Name, e.Type, e.StartDate, e.EndDate, e.City, e.GuestCount, e.Budget,
+		e.CollectionID, e.Status), &e)
+	if err != nil {
+		log.Printf("CreateEvent insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create event"})
+		return
+	}
+	if _, err := instantiateChecklist(e.ID); err != nil {
+		log.Printf("CreateEvent checklist error: %v", err)
+	}
+	actorID := ownerID
+	if plannerID != nil {
+		actorID = *plannerID
+	}
+	utils.RecordAudit(actorID, ownerID, &e.ID, "create event", map[string]any{"name": e.Name})
+
+	c.JSON(http.StatusCreated, gin.H{"event": e})
+}
+
+// GET /events
+// The user's own events: upcoming ones first, soonest first, then past
+// ones. Planners see their client events on /planner/dashboard.
+func GetMyEvents(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(eventSelect+`
+		WHERE user_id=$1
+		ORDER BY end_date < CURRENT_DATE, CASE WHEN end_date >= CURRENT_DATE THEN start_date END,
+		         start_date DESC, id
+	`, userID)
+	if err != nil {
+		log.Printf("GetMyEvents query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch events"})
+		return
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		var e models.Event
+		if err := scanEvent(rows, &e); err != nil {
+			log.Printf("GetMyEvents scan error: %v", err)
+			continue
+		}
+		events = append(events, e)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// GET /events/:id
+// The planning overview: the event, its bookings with their vendors, the
+// vendors on its shortlist, booking counts by status and the RSVP headcount.
+func GetEvent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	ov := models.EventOverview{
+		Event:         e,
+		BookingCounts: map[string]int{"pending": 0, "confirmed": 0, "cancelled": 0, "completed": 0},
+		Bookings:      []models.EventBooking{},
+		SavedVendors:  []models.SavedVendorItem{},
+	}
+	ov.DaysUntil = daysUntil(e.StartDate)
+
+	rows, err := config.DB.Query(`
+		SELECT b.id, b.user_id, b.vendor_id, b.event_id, b.event_date, b.status, b.total_price::float8, COALESCE(b.notes, ''),
+		       b.created_at, b.updated_at,
+		       v.id, v.vendor_id, v.title, COALESCE(v.slug, ''), v.description, v.category, v.price_range,
+		       v.location, COALESCE(v.photos, ARRAY[]::text[]), v.created_at, v.updated_at
+		FROM bookings b
+		JOIN vendors v ON v.id = b.vendor_id
+		WHERE b.event_id = $1
+		ORDER BY b.event_date, b.id
+	`, e.ID)
+	if err != nil {
+		log.Printf("GetEvent bookings query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch event"})
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var b models.EventBooking
+		if err := rows.Scan(
+			&b.ID, &b.UserID, &b.VendorID, &b.EventID, &b.EventDate, &b.Status, &b.TotalPrice, &b.Notes, &b.CreatedAt, &b.UpdatedAt,
+			&b.Vendor.ID, &b.Vendor.VendorID, &b.Vendor.Title, &b.Vendor.Slug, &b.Vendor.Description,
+			&b.Vendor.Category, &b.Vendor.PriceRange, &b.Vendor.Location, &b.Vendor.Photos,
+			&b.Vendor.CreatedAt, &b.Vendor.UpdatedAt,
+		); err != nil {
+			log.Printf("GetEvent bookings scan error: %v", err)
+			continue
+		}
+		ov.BookingCounts[b.Status]++
+		ov.Bookings = append(ov.Bookings, b)
+	}
+
+	if ov.Headcount, err = eventHeadcount(e.ID); err != nil {
+		log.Printf("GetEvent headcount error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch event"})
+		return
+	}
+
+	if e.CollectionID != nil {
+		if ov.SavedVendors, err = collectionVendors(e.UserID, e.CollectionID, userID); err != nil {
+			log.Printf("GetEvent saved vendors error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch event"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"event": ov})
+}
+
+// PATCH /events/:id
+// body: any of the POST /events fields, plus "status"
+func UpdateEvent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	var input eventInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	oldType := e.Type
+	if err := input.apply(&e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.CollectionID != nil {
+		if e.CollectionID, ok = ownedCollection(c, e.UserID, *input.CollectionID); !ok {
+			return
+		}
+	}
+	if input.PlannerID != nil {
+		if userID != e.UserID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the event owner can change its planner"})
+			return
+		}
+		e.PlannerID = nil
+		if *input.PlannerID != 0 {
+			var active bool
+			config.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM planner_clients WHERE planner_id=$1 AND client_id=$2 AND status='active')`,
+				*input.PlannerID, e.UserID).Scan(&active)
+			if !active {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "planner_id must be one of your planners"})
+				return
+			}
+			e.PlannerID = input.PlannerID
+		}
+	}
+
+	err := scanEvent(config.DB.QueryRow(`
+		UPDATE events SET name=$2, type=$3, start_date=$4, end_date=$5, city=NULLIF($6, ''), guest_count=$7,
+		       budget=$8, collection_id=$9, status=$10, planner_id=$11, updated_at=NOW()
+		WHERE id=$1
+		RETURNING `+eventColumns,
+		e.ID, e.Name, e.Type, e.StartDate, e.EndDate, e.City, e.GuestCount, e.Budget, e.CollectionID, e.Status,
+		e.PlannerID), &e)
+	if err != nil {
+		log.Printf("UpdateEvent error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update event"})
+		return
+	}
+	if input.StartDate != nil {
+		if err := rescheduleChecklist(e.ID); err != nil {
+			log.Printf("UpdateEvent reschedule error: %v", err)
+		}
+	}
+	if e.Type != oldType {
+		if err := clearChecklistType(e.ID, oldType); err != nil {
+			log.Printf("UpdateEvent checklist error: %v", err)
+		}
+		if _, err := instantiateChecklist(e.ID); err != nil {
+			log.Printf("UpdateEvent checklist error: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"event": e})
+}
+
+// DELETE /events/:id
+// Owner only. Bookings stay; they are only detached from the event.
+func DeleteEvent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+	if e.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the event owner can delete it"})
+		return
+	}
+
+	if _, err := config.DB.Exec(`DELETE FROM events WHERE id=$1`, e.ID); err != nil {
+		log.Printf("DeleteEvent error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete event"})
+		return
+	}
+	// audit_log.event_id cannot point at a deleted event, so the entry names
+	// it in its details instead of going through AuditTrailMiddleware
+	c.Set("audit_actor_id", 0)
+	utils.RecordAudit(userID, e.UserID, nil, "delete event", map[string]any{"event_id": e.ID, "name": e.Name})
+
+	c.JSON(http.StatusOK, gin.H{"message": "event deleted"})
+}
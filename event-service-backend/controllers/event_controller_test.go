@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/dharmaseervi/event-service-backend/models"
+)
+
+func TestEventInputApplyMovesWholeEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		newStart   string
+		wantEnd    string
+	}{
+		{"one-day event earlier", "2025-12-10", "2025-12-10", "2025-12-05", "2025-12-05"},
+		{"one-day event later", "2025-12-10", "2025-12-10", "2025-12-15", "2025-12-15"},
+		{"multi-day event earlier", "2025-12-10", "2025-12-12", "2025-12-05", "2025-12-07"},
+		{"multi-day event later", "2025-12-10", "2025-12-12", "2025-12-15", "2025-12-17"},
+		{"across a month", "2025-12-30", "2026-01-01", "2026-02-27", "2026-03-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := models.Event{Name: "Wedding", Type: models.EventTypes[0], StartDate: tt.start, EndDate: tt.end}
+			if err := (eventInput{StartDate: &tt.newStart}).apply(&e); err != nil {
+				t.Fatal(err)
+			}
+			if e.StartDate != tt.newStart || e.EndDate != tt.wantEnd {
+				t.Errorf("got %s to %s, want %s to %s", e.StartDate, e.EndDate, tt.newStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestEventInputApplyKeepsGivenEndDate(t *testing.T) {
+	e := models.Event{Name: "Wedding", Type: models.EventTypes[0], StartDate: "2025-12-10", EndDate: "2025-12-12"}
+	start, end := "2025-12-15", "2025-12-11"
+	err := (eventInput{StartDate: &start, EndDate: &end}).apply(&e)
+	if err == nil || err.Error() != "end_date must not be before start_date" {
+		t.Errorf("got %v, want the end_date error", err)
+	}
+}
@@ -0,0 +1,599 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	maxGuestImportBytes = 2 << 20 // 2 MB
+	maxRSVPNoteLength   = 500
+)
+
+const eventGuestSelect = `
+	SELECT id, event_id, name, email, phone, COALESCE(group_name, ''), plus_ones_allowed, rsvp_token,
+	       rsvp_status, plus_ones, meal_preference, COALESCE(rsvp_note, ''), invited_at, responded_at,
+	       created_at, updated_at
+	FROM event_guests
+`
+
+func scanEventGuest(scanner interface{ Scan(...any) error }, g *models.EventGuest) error {
+	return scanner.Scan(&g.ID, &g.EventID, &g.Name, &g.Email, &g.Phone, &g.Group, &g.PlusOnesAllowed, &g.RSVPToken,
+		&g.RSVPStatus, &g.PlusOnes, &g.MealPreference, &g.RSVPNote, &g.InvitedAt, &g.RespondedAt,
+		&g.CreatedAt, &g.UpdatedAt)
+}
+
+func newRSVPToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// guestInput is a guest as added by hand or read from a CSV row. PATCH
+// leaves nil fields alone.
+type guestInput struct {
+	Name            *string `json:"name"`
+	Email           *string `json:"email"`
+	Phone           *string `json:"phone"`
+	Group           *string `json:"group"`
+	PlusOnesAllowed *int    `json:"plus_ones_allowed"`
+}
+
+// apply validates the input on top of g. Errors are safe to show.
+func (in guestInput) apply(g *models.EventGuest) error {
+	optional := func(raw *string) *string {
+		if v := strings.TrimSpace(*raw); v != "" {
+			return &v
+		}
+		return nil
+	}
+	if in.Name != nil {
+		if g.Name = strings.TrimSpace(*in.Name); g.Name == "" {
+			return errors.New("name must not be empty")
+		}
+	}
+	if in.Email != nil {
+		if g.Email = optional(in.Email); g.Email != nil {
+			*g.Email = strings.ToLower(*g.Email)
+			if !strings.Contains(*g.Email, "@") {
+				return errors.New("invalid email")
+			}
+		}
+	}
+	if in.Phone != nil {
+		g.Phone = optional(in.Phone)
+	}
+	if in.Group != nil {
+		g.Group = strings.ToLower(strings.TrimSpace(*in.Group))
+	}
+	if in.PlusOnesAllowed != nil {
+		if *in.PlusOnesAllowed < 0 {
+			return errors.New("plus_ones_allowed must not be negative")
+		}
+		g.PlusOnesAllowed = *in.PlusOnesAllowed
+	}
+	return nil
+}
+
+// insertGuest adds g to the list. It reports false without an error when
+// the email is already on it.
+func insertGuest(g *models.EventGuest) (bool, error) {
+	err := config.DB.QueryRow(`
+		INSERT INTO event_guests (event_id, name, email, phone, group_name, plus_ones_allowed, rsvp_token)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+		ON CONFLICT (event_id, lower(email)) WHERE email IS NOT NULL DO NOTHING
+		RETURNING id, rsvp_token, rsvp_status, created_at, updated_at
+	`, g.EventID, g.Name, g.Email, g.Phone, g.Group, g.PlusOnesAllowed, newRSVPToken()).Scan(
+		&g.ID, &g.RSVPToken, &g.RSVPStatus, &g.CreatedAt, &g.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// eventGuestParam loads :guest_id on the event.
+func eventGuestParam(c *gin.Context, e models.Event) (models.EventGuest, bool) {
+	var g models.EventGuest
+	guestID, err := strconv.Atoi(c.Param("guest_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid guest id"})
+		return g, false
+	}
+	err = scanEventGuest(config.DB.QueryRow(eventGuestSelect+` WHERE id=$1 AND event_id=$2`, guestID, e.ID), &g)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "guest not found"})
+		return g, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return g, false
+	}
+	return g, true
+}
+
+// GET /events/:id/guests?group=&rsvp_status=
+// By name.
+func GetEventGuests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	var args utils.SQLArgs
+	where := "event_id = " + args.Add(e.ID)
+	if group := strings.ToLower(strings.TrimSpace(c.Query("group"))); group != "" {
+		where += " AND group_name = " + args.Add(group)
+	}
+	if status := c.Query("rsvp_status"); status != "" {
+		where += " AND rsvp_status = " + args.Add(status)
+	}
+
+	rows, err := config.DB.Query(eventGuestSelect+` WHERE `+where+` ORDER BY lower(name), id`, args...)
+	if err != nil {
+		log.Printf("GetEventGuests query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch guests"})
+		return
+	}
+	defer rows.Close()
+
+	guests := []models.EventGuest{}
+	for rows.Next() {
+		var g models.EventGuest
+		if err := scanEventGuest(rows, &g); err != nil {
+			log.Printf("GetEventGuests scan error: %v", err)
+			continue
+		}
+		guests = append(guests, g)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"guests": guests})
+}
+
+// POST /events/:id/guests
+// body: { "name", "email", "phone", "group", "plus_ones_allowed" }; only name is required
+func AddEventGuest(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	var input guestInput
+	if err := c.ShouldBindJSON(&input); err != nil || input.Name == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	g := models.EventGuest{EventID: e.ID}
+	if err := input.apply(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	added, err := insertGuest(&g)
+	if err != nil {
+		log.Printf("AddEventGuest insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add guest"})
+		return
+	}
+	if !added {
+		c.JSON(http.StatusConflict, gin.H{"error": "a guest with this email is already on the list"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"guest": g})
+}
+
+// POST /events/:id/guests/import
+// body: a CSV file, or multipart form field "file", with a header row of
+// name, email, phone, group and plus_ones_allowed (only name is required).
+// Rows whose email is already on the list are skipped.
+func ImportEventGuests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGuestImportBytes)
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file"})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	cr := csv.NewReader(body)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cols, err := readCSVHeader(cr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := cols["name"]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV header must include a name column"})
+		return
+	}
+	get := func(rec []string, name string) *string {
+		i, ok := cols[name]
+		if !ok || i >= len(rec) {
+			return nil
+		}
+		return &rec[i]
+	}
+
+	imported, skipped := 0, 0
+	rowErrors := []gin.H{}
+	for n := 1; ; n++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file (max 2 MB)"})
+				return
+			}
+			rowErrors = append(rowErrors, gin.H{"row": n, "error": err.Error()})
+			continue
+		}
+
+		input := guestInput{Name: get(rec, "name"), Email: get(rec, "email"), Phone: get(rec, "phone"), Group: get(rec, "group")}
+		if raw := get(rec, "plus_ones_allowed"); raw != nil && strings.TrimSpace(*raw) != "" {
+			allowed, err := strconv.Atoi(strings.TrimSpace(*raw))
+			if err != nil {
+				rowErrors = append(rowErrors, gin.H{"row": n, "error": "plus_ones_allowed must be a number"})
+				continue
+			}
+			input.PlusOnesAllowed = &allowed
+		}
+		if input.Name == nil {
+			input.Name = new(string)
+		}
+		g := models.EventGuest{EventID: e.ID}
+		if err := input.apply(&g); err != nil {
+			rowErrors = append(rowErrors, gin.H{"row": n, "error": err.Error()})
+			continue
+		}
+
+		added, err := insertGuest(&g)
+		if err != nil {
+			log.Printf("ImportEventGuests insert error: %v", err)
+			rowErrors = append(rowErrors, gin.H{"row": n, "error": "could not save guest"})
+			continue
+		}
+		if added {
+			imported++
+		} else {
+			skipped++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imported": imported, "skipped": skipped, "errors": rowErrors})
+}
+
+// PATCH /events/:id/guests/:guest_id
+// body: any of the POST fields
+func UpdateEventGuest(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+	g, ok := eventGuestParam(c, e)
+	if !ok {
+		return
+	}
+
+	var input guestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := input.apply(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := scanEventGuest(config.DB.QueryRow(`
+		UPDATE event_guests SET name=$2, email=$3, phone=$4, group_name=NULLIF($5, ''), plus_ones_allowed=$6,
+		       plus_ones=LEAST(plus_ones, $6), updated_at=NOW()
+		WHERE id=$1
+		RETURNING id, event_id, name, email, phone, COALESCE(group_name, ''), plus_ones_allowed, rsvp_token,
+		          rsvp_status, plus_ones, meal_preference, COALESCE(rsvp_note, ''), invited_at, responded_at,
+		          created_at, updated_at
+	`, g.ID, g.Name, g.Email, g.Phone, g.Group, g.PlusOnesAllowed), &g)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		c.JSON(http.StatusConflict, gin.H{"error": "a guest with this email is already on the list"})
+		return
+	}
+	if err != nil {
+		log.Printf("UpdateEventGuest error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update guest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"guest": g})
+}
+
+// DELETE /events/:id/guests/:guest_id
+func DeleteEventGuest(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+	g, ok := eventGuestParam(c, e)
+	if !ok {
+		return
+	}
+
+	if _, err := config.DB.Exec(`DELETE FROM event_guests WHERE id=$1`, g.ID); err != nil {
+		log.Printf("DeleteEventGuest error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete guest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "guest deleted"})
+}
+
+// POST /events/:id/guests/invite
+// body: { "guest_ids": [1, 2] } (optional; default: everyone not invited yet)
+// Marks the guests invited and returns their RSVP tokens for the app to
+// share. Guests who have an account with the same email also get a push.
+func SendEventInvites(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		GuestIDs []int64 `json:"guest_ids"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+			return
+		}
+	}
+
+	rows, err := config.DB.Query(`
+		UPDATE event_guests g SET invited_at = NOW(), updated_at = NOW()
+		WHERE g.event_id = $1 AND CASE WHEN $2::int[] IS NULL THEN g.invited_at IS NULL ELSE g.id = ANY($2) END
+		RETURNING g.id, g.name, g.rsvp_token, (SELECT u.id FROM users u WHERE lower(u.email) = g.email)
+	`, e.ID, pq.Int64Array(input.GuestIDs))
+	if err != nil {
+		log.Printf("SendEventInvites error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not send invites"})
+		return
+	}
+	defer rows.Close()
+
+	host := userDisplayName(userID)
+	invites := []gin.H{}
+	for rows.Next() {
+		var guestID int
+		var name, token string
+		var guestUserID sql.NullInt64
+		if err := rows.Scan(&guestID, &name, &token, &guestUserID); err != nil {
+			log.Printf("SendEventInvites scan error: %v", err)
+			continue
+		}
+		if guestUserID.Valid {
+			go utils.NotifyUser(int(guestUserID.Int64), "You're invited to "+e.Name, host+" would love to know if you can make it",
+				map[string]string{
+					"type":       "rsvp_invite",
+					"rsvp_token": token,
+				})
+		}
+		invites = append(invites, gin.H{"guest_id": guestID, "name": name, "rsvp_token": token})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invites": invites})
+}
+
+// GET /events/:id/headcount
+func GetEventHeadcount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+
+	hc, err := eventHeadcount(e.ID)
+	if err != nil {
+		log.Printf("GetEventHeadcount error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch headcount"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"headcount": hc})
+}
+
+func eventHeadcount(eventID int) (models.GuestHeadcount, error) {
+	hc := models.GuestHeadcount{Meals: map[string]int{}, ByGroup: map[string]int{}}
+
+	rows, err := config.DB.Query(`
+		SELECT rsvp_status, COALESCE(meal_preference, 'unknown'), COALESCE(group_name, ''),
+		       COUNT(*), SUM(1 + plus_ones)
+		FROM event_guests
+		WHERE event_id = $1
+		GROUP BY 1, 2, 3
+	`, eventID)
+	if err != nil {
+		return hc, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status, meal, group string
+		var guests, people int
+		if err := rows.Scan(&status, &meal, &group, &guests, &people); err != nil {
+			return hc, err
+		}
+		hc.Guests += guests
+		switch status {
+		case "pending":
+			hc.Pending += guests
+		case "declined":
+			hc.Declined += guests
+		case "attending":
+			hc.Attending += guests
+			hc.People += people
+			hc.Meals[meal] += people
+			if group != "" {
+				hc.ByGroup[group] += people
+			}
+		}
+	}
+	return hc, rows.Err()
+}
+
+// rsvpGuest loads the guest for :token with what the RSVP page shows.
+func rsvpGuest(c *gin.Context) (guestID, hostID int, invite models.RSVPInvite, ok bool) {
+	var status string
+	err := config.DB.QueryRow(`
+		SELECT g.id, e.user_id, e.name, e.type, e.start_date::text, e.end_date::text, COALESCE(e.city, ''),
+		       COALESCE(u.full_name, ''), g.name, g.plus_ones_allowed, g.rsvp_status, g.plus_ones,
+		       g.meal_preference, e.status
+		FROM event_guests g
+		JOIN events e ON e.id = g.event_id
+		JOIN users u ON u.id = e.user_id
+		WHERE g.rsvp_token = $1
+	`, c.Param("token")).Scan(&guestID, &hostID, &invite.EventName, &invite.EventType, &invite.StartDate,
+		&invite.EndDate, &invite.City, &invite.HostName, &invite.GuestName, &invite.PlusOnesAllowed,
+		&invite.RSVPStatus, &invite.PlusOnes, &invite.MealPreference, &status)
+	if err == sql.ErrNoRows {
+		utils.RespondWithError(c, http.StatusNotFound, "Invitation not found")
+		return
+	}
+	if err != nil {
+		log.Printf("rsvpGuest error: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not load invitation")
+		return
+	}
+	invite.Open = status != "cancelled" && invite.EndDate >= time.Now().Format(time.DateOnly)
+	return guestID, hostID, invite, true
+}
+
+// GET /rsvp/:token
+// Public: the token is the guest's credential.
+func GetRSVP(c *gin.Context) {
+	_, _, invite, ok := rsvpGuest(c)
+	if !ok {
+		return
+	}
+	utils.RespondWithJSON(c, http.StatusOK, invite)
+}
+
+// POST /rsvp/:token
+// body: { "status": "attending" | "declined", "plus_ones": 1, "meal_preference": "veg", "note": "..." }
+// Public. Guests can change their answer until the event is over.
+func RespondRSVP(c *gin.Context) {
+	guestID, hostID, invite, ok := rsvpGuest(c)
+	if !ok {
+		return
+	}
+	if !invite.Open {
+		utils.RespondWithError(c, http.StatusGone, "This event is no longer taking RSVPs")
+		return
+	}
+
+	var input struct {
+		Status         string  `json:"status"`
+		PlusOnes       int     `json:"plus_ones"`
+		MealPreference *string `json:"meal_preference"`
+		Note           string  `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+	switch {
+	case input.Status != "attending" && input.Status != "declined":
+		utils.RespondWithError(c, http.StatusBadRequest, "status must be attending or declined")
+		return
+	case input.PlusOnes < 0 || input.PlusOnes > invite.PlusOnesAllowed:
+		utils.RespondWithError(c, http.StatusBadRequest, "plus_ones must be between 0 and "+strconv.Itoa(invite.PlusOnesAllowed))
+		return
+	case input.MealPreference != nil && !slices.Contains(models.MealPreferences, *input.MealPreference):
+		utils.RespondWithError(c, http.StatusBadRequest, "meal_preference must be one of "+strings.Join(models.MealPreferences, ", "))
+		return
+	case len(input.Note) > maxRSVPNoteLength:
+		utils.RespondWithError(c, http.StatusBadRequest, "note is too long")
+		return
+	}
+	if input.Status == "declined" {
+		input.PlusOnes, input.MealPreference = 0, nil
+	}
+
+	_, err := config.DB.Exec(`
+		UPDATE event_guests SET rsvp_status=$2, plus_ones=$3, meal_preference=$4, rsvp_note=NULLIF($5, ''),
+		       responded_at=NOW(), updated_at=NOW()
+		WHERE id=$1
+	`, guestID, input.Status, input.PlusOnes, input.MealPreference, strings.TrimSpace(input.Note))
+	if err != nil {
+		log.Printf("RespondRSVP error: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not save your response")
+		return
+	}
+
+	verb := "can't make it to"
+	if input.Status == "attending" {
+		verb = "is coming to"
+	}
+	go utils.NotifyUser(hostID, "New RSVP", invite.GuestName+" "+verb+" "+invite.EventName, map[string]string{
+		"type": "rsvp",
+	})
+
+	invite.RSVPStatus, invite.PlusOnes, invite.MealPreference = input.Status, input.PlusOnes, input.MealPreference
+	utils.RespondWithJSON(c, http.StatusOK, invite)
+}
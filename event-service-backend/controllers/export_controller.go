@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/gin-gonic/gin"
+)
+
+// rows fetched per round trip while streaming an export
+const exportBatchSize = 500
+
+// exportSpec describes one export. Query must select the keyset id as its
+// first column, filter on "> $1", and end with "ORDER BY <id> LIMIT $2";
+// any extra Args are bound from $3 onwards. Columns names every selected
+// column in order and becomes the CSV header / JSONL keys.
+type exportSpec struct {
+	Name    string
+	Columns []string
+	Query   string
+	Args    []any
+}
+
+// GET /exports/vendor/bookings?format=csv|jsonl
+func ExportVendorBookings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	streamExport(c, exportSpec{
+		Name:    "bookings",
+		Columns: bookingExportColumns,
+		Query: bookingExportSelect + `
+			WHERE b.id > $1 AND v.vendor_id = $3
+			ORDER BY b.id LIMIT $2`,
+		Args: []any{userID},
+	})
+}
+
+// GET /exports/vendor/deals?format=csv|jsonl
+func ExportVendorDeals(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	streamExport(c, exportSpec{
+		Name: "deals",
+		Columns: []string{"id", "vendor_id", "vendor_title", "title", "description", "discount_percent",
+			"original_price", "deal_price", "start_date", "end_date", "created_at"},
+		Query: `
+			SELECT d.id, d.vendor_id, v.title, d.title, COALESCE(d.description, ''), d.discount_percent,
+			       d.original_price, d.deal_price, d.start_date, d.end_date, d.created_at
+			FROM vendor_deals d
+			JOIN vendors v ON v.id = d.vendor_id
+			WHERE d.id > $1 AND v.vendor_id = $3
+			ORDER BY d.id LIMIT $2`,
+		Args: []any{userID},
+	})
+}
+
+// GET /admin/exports/users?format=csv|jsonl
+func ExportUsers(c *gin.Context) {
+	streamExport(c, exportSpec{
+		Name:    "users",
+		Columns: []string{"id", "clerk_id", "full_name", "email", "role", "created_at", "updated_at"},
+		Query: `
+			SELECT id, clerk_id, COALESCE(full_name, ''), COALESCE(email, ''), COALESCE(role, 'user'),
+			       created_at, updated_at
+			FROM users
+			WHERE id > $1
+			ORDER BY id LIMIT $2`,
+	})
+}
+
+// GET /admin/exports/vendors?format=csv|jsonl
+func ExportVendors(c *gin.Context) {
+	streamExport(c, exportSpec{
+		Name: "vendors",
+		Columns: []string{"id", "vendor_id", "title", "description", "category", "price_range", "location",
+			"photos", "rating", "featured", "external_ref", "created_at", "updated_at"},
+		Query: `
+			SELECT id, vendor_id, title, COALESCE(description, ''), COALESCE(category, ''),
+			       COALESCE(price_range, ''), COALESCE(location, ''),
+			       array_to_string(COALESCE(photos, ARRAY[]::text[]), '|'),
+			       COALESCE(rating, 0)::float8, COALESCE(featured, false), COALESCE(external_ref, ''),
+			       created_at, updated_at
+			FROM vendors
+			WHERE id > $1
+			ORDER BY id LIMIT $2`,
+	})
+}
+
+// GET /admin/exports/bookings?format=csv|jsonl
+func ExportBookings(c *gin.Context) {
+	streamExport(c, exportSpec{
+		Name:    "bookings",
+		Columns: bookingExportColumns,
+		Query: bookingExportSelect + `
+			WHERE b.id > $1
+			ORDER BY b.id LIMIT $2`,
+	})
+}
+
+var bookingExportColumns = []string{"id", "user_id", "vendor_id", "vendor_title", "event_date", "status",
+	"total_price", "notes", "created_at", "updated_at"}
+
+const bookingExportSelect = `
+	SELECT b.id, b.user_id, b.vendor_id, COALESCE(v.title, ''), b.event_date, b.status,
+	       b.total_price::float8, COALESCE(b.notes, ''), b.created_at, b.updated_at
+	FROM bookings b
+	LEFT JOIN vendors v ON v.id = b.vendor_id`
+
+// streamExport writes the export batch by batch using keyset pagination on
+// the id column, so memory use stays flat however large the table is.
+func streamExport(c *gin.Context, spec exportSpec) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "jsonl" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or jsonl"})
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", spec.Name, time.Now().Format("20060102"), format)
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv")
+	} else {
+		c.Header("Content-Type", "application/x-ndjson")
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	var csvWriter *csv.Writer
+	jsonEncoder := json.NewEncoder(c.Writer)
+	started := false
+
+	var cursor int64
+	for {
+		args := append([]any{cursor, exportBatchSize}, spec.Args...)
+		rows, err := config.DB.Query(spec.Query, args...)
+		if err != nil {
+			log.Printf("Export %s query error: %v", spec.Name, err)
+			if !started {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed"})
+			}
+			return
+		}
+
+		if !started {
+			c.Status(http.StatusOK)
+			if format == "csv" {
+				csvWriter = csv.NewWriter(c.Writer)
+				csvWriter.Write(spec.Columns)
+			}
+			started = true
+		}
+
+		n := 0
+		values := make([]any, len(spec.Columns))
+		ptrs := make([]any, len(spec.Columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		for rows.Next() {
+			if err := rows.Scan(ptrs...); err != nil {
+				log.Printf("Export %s scan error: %v", spec.Name, err)
+				rows.Close()
+				return
+			}
+			cursor, _ = values[0].(int64)
+			n++
+
+			if format == "csv" {
+				record := make([]string, len(values))
+				for i, v := range values {
+					record[i] = exportValueString(v)
+				}
+				csvWriter.Write(record)
+			} else {
+				obj := make(map[string]any, len(values))
+				for i, v := range values {
+					if b, ok := v.([]byte); ok {
+						v = string(b)
+					}
+					obj[spec.Columns[i]] = v
+				}
+				jsonEncoder.Encode(obj)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			log.Printf("Export %s rows error: %v", spec.Name, err)
+			return
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+		}
+		c.Writer.Flush()
+
+		if n < exportBatchSize {
+			return
+		}
+	}
+}
+
+func exportValueString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case string:
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// vendorSorts returns the ?sort= keys for lists of vendor listings, taking
+// the caller's position from ?lat=&lng=. col prefixes the vendors columns
+// (e.g. "v.").
+func vendorSorts(c *gin.Context, col string, args *utils.SQLArgs) (map[string]utils.SortKey, error) {
+	var lat, lng *float64
+	if v, err := strconv.ParseFloat(c.Query("lat"), 64); err == nil {
+		lat = &v
+	}
+	if v, err := strconv.ParseFloat(c.Query("lng"), 64); err == nil {
+		lng = &v
+	}
+	return utils.VendorSorts(col, c.Query("sort"), lat, lng, args)
+}
@@ -0,0 +1,607 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxMessageLength      = 4000
+	maxMessageAttachments = 10
+	messagePageSize       = 50
+)
+
+// conversationSelect lists the conversations $1 takes part in, each with its
+// latest message and how far both sides have read. conversationColumns and
+// conversationFrom are its halves, for queries that select more columns.
+const conversationSelect = conversationColumns + conversationFrom
+
+const conversationColumns = `
+	SELECT cv.id, cv.vendor_id, COALESCE(v.title, ''), cv.user_id, COALESCE(u.full_name, ''), cv.booking_id,
+	       me.role, me.unread_count, me.last_read_message_id,
+	       COALESCE((SELECT MIN(o.last_read_message_id) FROM conversation_participants o
+	                 WHERE o.conversation_id = cv.id AND o.user_id <> $1), 0),
+	       cv.last_message_at, cv.created_at,
+	       m.id, m.sender_id, COALESCE(s.full_name, ''), m.body, m.attachments, m.created_at`
+
+const conversationFrom = `
+	FROM conversations cv
+	JOIN conversation_participants me ON me.conversation_id = cv.id AND me.user_id = $1
+	JOIN vendors v ON v.id = cv.vendor_id
+	JOIN users u ON u.id = cv.user_id
+	LEFT JOIN LATERAL (
+		SELECT * FROM messages WHERE conversation_id = cv.id ORDER BY id DESC LIMIT 1
+	) m ON TRUE
+	LEFT JOIN users s ON s.id = m.sender_id
+`
+
+// readMarks says how far the caller and everyone else have read.
+type readMarks struct {
+	mine, others int
+}
+
+// apply marks a message read when everyone but its sender has seen it.
+func (r readMarks) apply(viewerID int, m *models.Message) {
+	if m.SenderID != nil && *m.SenderID == viewerID {
+		m.Read = m.ID <= r.others
+	} else {
+		m.Read = m.ID <= r.mine
+	}
+}
+
+// scanConversation reads a conversationSelect row; extra receives any
+// columns selected after conversationColumns.
+func scanConversation(scanner interface{ Scan(...any) error }, viewerID int, cv *models.Conversation, extra ...any) error {
+	var marks readMarks
+	var msgID *int
+	var senderID *int
+	var senderName string
+	var body *string
+	var attachments []byte
+	var sentAt sql.NullTime
+	dest := []any{&cv.ID, &cv.VendorID, &cv.VendorTitle, &cv.UserID, &cv.CustomerName, &cv.BookingID,
+		&cv.Role, &cv.UnreadCount, &marks.mine, &marks.others, &cv.LastMessageAt, &cv.CreatedAt,
+		&msgID, &senderID, &senderName, &body, &attachments, &sentAt}
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	cv.LastMessage = nil
+	if msgID != nil {
+		m := &models.Message{ID: *msgID, ConversationID: cv.ID, SenderID: senderID, SenderName: senderName,
+			Body: *body, CreatedAt: sentAt.Time}
+		decodeAttachments(attachments, m)
+		marks.apply(viewerID, m)
+		cv.LastMessage = m
+	}
+	return nil
+}
+
+func decodeAttachments(raw []byte, m *models.Message) {
+	m.Attachments = []models.MessageAttachment{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &m.Attachments); err != nil {
+			log.Printf("message %d attachments: %v", m.ID, err)
+		}
+	}
+}
+
+// messageInput is the body of a new message: text, attachments or both.
+type messageInput struct {
+	Body        string                     `json:"body"`
+	Attachments []models.MessageAttachment `json:"attachments"`
+}
+
+// clean validates the message. Errors are safe to show.
+func (in *messageInput) clean() error {
+	in.Body = strings.TrimSpace(in.Body)
+	if len(in.Body) > maxMessageLength {
+		return errors.New("message must be at most " + strconv.Itoa(maxMessageLength) + " characters")
+	}
+	if len(in.Attachments) > maxMessageAttachments {
+		return errors.New("at most " + strconv.Itoa(maxMessageAttachments) + " attachments per message")
+	}
+	for i := range in.Attachments {
+		a := &in.Attachments[i]
+		a.URL = strings.TrimSpace(a.URL)
+		if !strings.HasPrefix(a.URL, "https://") && !strings.HasPrefix(a.URL, "http://") {
+			return errors.New("attachment url must be an http(s) link")
+		}
+		a.Name = strings.TrimSpace(a.Name)
+		a.ContentType = strings.TrimSpace(a.ContentType)
+		if a.Size < 0 {
+			return errors.New("attachment size must not be negative")
+		}
+	}
+	if in.Attachments == nil {
+		in.Attachments = []models.MessageAttachment{}
+	}
+	return nil
+}
+
+func (in messageInput) empty() bool {
+	return in.Body == "" && len(in.Attachments) == 0
+}
+
+// insertMessage stores a message; the trigger bumps the thread and the
+// other side's unread count.
+func insertMessage(tx *sql.Tx, conversationID, senderID int, in messageInput) (models.Message, error) {
+	m := models.Message{ConversationID: conversationID, SenderID: &senderID, Body: in.Body, Attachments: in.Attachments}
+	raw, err := json.Marshal(in.Attachments)
+	if err != nil {
+		return m, err
+	}
+	err = tx.QueryRow(`
+		INSERT INTO messages (conversation_id, sender_id, body, attachments) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, conversationID, senderID, in.Body, string(raw)).Scan(&m.ID, &m.CreatedAt)
+	m.SenderName = userDisplayName(senderID)
+	return m, err
+}
+
+// notifyNewMessage pushes a message to everyone in the conversation but its
+// sender. Vendors write under their listing's name.
+func notifyNewMessage(cv models.Conversation, m models.Message) {
+	title := m.SenderName
+	if cv.Role == "vendor" {
+		title = cv.VendorTitle
+	}
+	body := m.Body
+	if r := []rune(body); len(r) > 140 {
+		body = string(r[:137]) + "..."
+	}
+	if body == "" {
+		body = "Sent " + plural(len(m.Attachments), "attachment")
+	}
+
+	rows, err := config.DB.Query(`SELECT user_id FROM conversation_participants WHERE conversation_id=$1 AND user_id<>$2`,
+		cv.ID, *m.SenderID)
+	if err != nil {
+		log.Printf("notifyNewMessage error: %v", err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int
+		if rows.Scan(&userID) == nil {
+			go utils.NotifyUser(userID, title, body, map[string]string{
+				"type":            "message",
+				"conversation_id": strconv.Itoa(cv.ID),
+				"message_id":      strconv.Itoa(m.ID),
+			})
+		}
+	}
+}
+
+// loadConversation loads :id for one of its participants. It writes the
+// error response itself and returns ok=false when the caller should stop.
+func loadConversation(c *gin.Context, userID int) (models.Conversation, bool) {
+	var cv models.Conversation
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return cv, false
+	}
+	return conversationByID(c, userID, id)
+}
+
+func conversationByID(c *gin.Context, userID, id int) (models.Conversation, bool) {
+	var cv models.Conversation
+	err := scanConversation(config.DB.QueryRow(conversationSelect+` WHERE cv.id = $2`, userID, id), userID, &cv)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+		return cv, false
+	}
+	if err != nil {
+		log.Printf("loadConversation error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return cv, false
+	}
+	return cv, true
+}
+
+// POST /conversations
+// body: { "vendor_id": 7, "booking_id": 12, "body": "Hi!", "attachments": [{ "url", "name", "content_type", "size" }] }
+// Opens the thread with a vendor, or about a booking, and posts the first
+// message if there is one. Customers message listings; vendors can start a
+// thread only about a booking with them. Opening an existing thread returns it.
+func StartConversation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		VendorID  int  `json:"vendor_id"`
+		BookingID *int `json:"booking_id"`
+		messageInput
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := input.clean(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	customerID := userID
+	if input.BookingID != nil {
+		var bookingVendorID int
+		if err := config.DB.QueryRow(`SELECT user_id, vendor_id FROM bookings WHERE id=$1`, *input.BookingID).
+			Scan(&customerID, &bookingVendorID); err != nil || (input.VendorID != 0 && input.VendorID != bookingVendorID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid booking_id"})
+			return
+		}
+		input.VendorID = bookingVendorID
+	}
+	if input.VendorID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vendor_id or booking_id is required"})
+		return
+	}
+
+	var ownerID sql.NullInt64
+	if err := config.DB.QueryRow(`SELECT vendor_id FROM vendors WHERE id=$1`, input.VendorID).Scan(&ownerID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "vendor not found"})
+		return
+	}
+	vendorUserID := int(ownerID.Int64)
+	if userID != customerID && userID != vendorUserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid booking_id"})
+		return
+	}
+	if vendorUserID == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "this vendor cannot receive messages yet"})
+		return
+	}
+	if vendorUserID == customerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "you cannot message your own listing"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var conversationID int
+	err = tx.QueryRow(`
+		INSERT INTO conversations (vendor_id, user_id, booking_id) VALUES ($1, $2, $3)
+		ON CONFLICT (vendor_id, user_id, COALESCE(booking_id, 0)) DO UPDATE SET vendor_id = EXCLUDED.vendor_id
+		RETURNING id
+	`, input.VendorID, customerID, input.BookingID).Scan(&conversationID)
+	if err == nil {
+		_, err = tx.Exec(`
+			INSERT INTO conversation_participants (conversation_id, user_id, role)
+			VALUES ($1, $2, 'customer'), ($1, $3, 'vendor')
+			ON CONFLICT DO NOTHING
+		`, conversationID, customerID, vendorUserID)
+	}
+	if err != nil {
+		log.Printf("StartConversation error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not start conversation"})
+		return
+	}
+	var m models.Message
+	if !input.empty() {
+		if m, err = insertMessage(tx, conversationID, userID, input.messageInput); err != nil {
+			log.Printf("StartConversation message error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not send message"})
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+
+	cv, ok := conversationByID(c, userID, conversationID)
+	if !ok {
+		return
+	}
+	if m.ID != 0 {
+		notifyNewMessage(cv, m)
+	}
+	c.JSON(http.StatusCreated, gin.H{"conversation": cv})
+}
+
+// GET /conversations?limit=&cursor=
+// The caller's threads, most recently active first. ?role=vendor is the
+// vendor-side inbox (?vendor_id= narrows it to one listing); ?role=customer
+// the threads the caller started as a customer.
+func GetMyConversations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := utils.ParsePage(c, map[string]utils.SortKey{
+		utils.SortNewest: {Expr: "COALESCE(cv.last_message_at, cv.created_at)", Desc: true, Cast: "timestamptz"},
+	}, utils.SortNewest, "cv.id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	args := utils.SQLArgs{userID}
+	where := ` WHERE TRUE`
+	if role := c.Query("role"); role != "" {
+		if role != "customer" && role != "vendor" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "role must be customer or vendor"})
+			return
+		}
+		where += ` AND me.role = ` + args.Add(role)
+	}
+	if raw := c.Query("vendor_id"); raw != "" {
+		vendorID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor_id"})
+			return
+		}
+		where += ` AND cv.vendor_id = ` + args.Add(vendorID)
+	}
+	if c.Query("unread") == "true" {
+		where += ` AND me.unread_count > 0`
+	}
+
+	where += ` AND ` + page.Where(args.Add)
+
+	rows, err := config.DB.Query(conversationColumns+`, `+page.SortValue()+conversationFrom+where+`
+		ORDER BY `+page.OrderBy()+`
+		LIMIT `+args.Add(page.FetchLimit()), args...)
+	if err != nil {
+		log.Printf("GetMyConversations query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch conversations"})
+		return
+	}
+	defer rows.Close()
+
+	conversations := []models.Conversation{}
+	for rows.Next() {
+		var cv models.Conversation
+		var sortValue string
+		if err := scanConversation(rows, userID, &cv, &sortValue); err != nil {
+			log.Printf("GetMyConversations scan error: %v", err)
+			continue
+		}
+		page.Track(sortValue, cv.ID)
+		conversations = append(conversations, cv)
+	}
+
+	n, next := page.Done()
+	utils.RespondWithPage(c, conversations[:n], &page, next)
+}
+
+// GET /conversations/unread
+// Unread message totals for the tab badges.
+func GetUnreadMessageCounts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	counts := gin.H{"customer": 0, "vendor": 0}
+	total := 0
+	rows, err := config.DB.Query(`
+		SELECT role, COALESCE(SUM(unread_count), 0) FROM conversation_participants WHERE user_id=$1 GROUP BY role
+	`, userID)
+	if err != nil {
+		log.Printf("GetUnreadMessageCounts error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch unread counts"})
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var role string
+		var n int
+		if rows.Scan(&role, &n) == nil {
+			counts[role] = n
+			total += n
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": total, "by_role": counts})
+}
+
+// GET /conversations/:id
+// The thread with its participants and a page of messages, oldest first.
+// ?before=<message id> pages back through history.
+func GetConversation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	cv, ok := loadConversation(c, userID)
+	if !ok {
+		return
+	}
+
+	before := 0
+	if raw := c.Query("before"); raw != "" {
+		var err error
+		if before, err = strconv.Atoi(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid before"})
+			return
+		}
+	}
+
+	var err error
+	if cv.Participants, err = conversationParticipants(cv.ID); err != nil {
+		log.Printf("GetConversation participants error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch conversation"})
+		return
+	}
+	marks := readMarks{others: -1}
+	for _, p := range cv.Participants {
+		if p.UserID == userID {
+			marks.mine = p.LastReadMessageID
+		} else if marks.others < 0 || p.LastReadMessageID < marks.others {
+			marks.others = p.LastReadMessageID
+		}
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT m.id, m.sender_id, COALESCE(s.full_name, ''), m.body, m.attachments, m.created_at
+		FROM messages m
+		LEFT JOIN users s ON s.id = m.sender_id
+		WHERE m.conversation_id = $1 AND ($2 = 0 OR m.id < $2)
+		ORDER BY m.id DESC
+		LIMIT $3
+	`, cv.ID, before, messagePageSize+1)
+	if err != nil {
+		log.Printf("GetConversation messages error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch messages"})
+		return
+	}
+	defer rows.Close()
+
+	messages := []models.Message{}
+	for rows.Next() {
+		m := models.Message{ConversationID: cv.ID}
+		var attachments []byte
+		if err := rows.Scan(&m.ID, &m.SenderID, &m.SenderName, &m.Body, &attachments, &m.CreatedAt); err != nil {
+			log.Printf("GetConversation scan error: %v", err)
+			continue
+		}
+		decodeAttachments(attachments, &m)
+		marks.apply(userID, &m)
+		messages = append(messages, m)
+	}
+	hasMore := len(messages) > messagePageSize
+	if hasMore {
+		messages = messages[:messagePageSize]
+	}
+	slices.Reverse(messages)
+
+	c.JSON(http.StatusOK, gin.H{"conversation": cv, "messages": messages, "has_more": hasMore})
+}
+
+func conversationParticipants(conversationID int) ([]models.ConversationParticipant, error) {
+	rows, err := config.DB.Query(`
+		SELECT p.user_id, COALESCE(u.full_name, ''), p.role, p.unread_count, p.last_read_message_id, p.last_read_at
+		FROM conversation_participants p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.conversation_id = $1
+		ORDER BY p.role
+	`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	participants := []models.ConversationParticipant{}
+	for rows.Next() {
+		var p models.ConversationParticipant
+		if err := rows.Scan(&p.UserID, &p.Name, &p.Role, &p.UnreadCount, &p.LastReadMessageID, &p.LastReadAt); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+	return participants, rows.Err()
+}
+
+// POST /conversations/:id/messages
+// body: { "body": "...", "attachments": [...] }
+func SendMessage(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	cv, ok := loadConversation(c, userID)
+	if !ok {
+		return
+	}
+
+	var input messageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := input.clean(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.empty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body or attachments are required"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	defer tx.Rollback()
+	m, err := insertMessage(tx, cv.ID, userID, input)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("SendMessage error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not send message"})
+		return
+	}
+
+	notifyNewMessage(cv, m)
+	c.JSON(http.StatusCreated, gin.H{"message": m})
+}
+
+// POST /conversations/:id/read
+// body (optional): { "message_id": 42 } — defaults to the latest message.
+// Read marks only move forward.
+func MarkConversationRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	cv, ok := loadConversation(c, userID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		MessageID int `json:"message_id"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+			return
+		}
+	}
+
+	var p models.ConversationParticipant
+	err := config.DB.QueryRow(`
+		WITH target AS (
+			SELECT COALESCE(MAX(id), 0) AS id FROM messages
+			WHERE conversation_id = $1 AND ($3 = 0 OR id <= $3)
+		)
+		UPDATE conversation_participants p SET
+			last_read_message_id = GREATEST(p.last_read_message_id, target.id),
+			last_read_at = CASE WHEN target.id > p.last_read_message_id THEN NOW() ELSE p.last_read_at END,
+			unread_count = (SELECT COUNT(*) FROM messages m
+			                WHERE m.conversation_id = $1 AND m.sender_id IS DISTINCT FROM $2
+			                  AND m.id > GREATEST(p.last_read_message_id, target.id))
+		FROM target
+		WHERE p.conversation_id = $1 AND p.user_id = $2
+		RETURNING p.user_id, p.role, p.unread_count, p.last_read_message_id, p.last_read_at
+	`, cv.ID, userID, input.MessageID).Scan(&p.UserID, &p.Role, &p.UnreadCount, &p.LastReadMessageID, &p.LastReadAt)
+	if err != nil {
+		log.Printf("MarkConversationRead error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not mark read"})
+		return
+	}
+	p.Name = userDisplayName(userID)
+
+	c.JSON(http.StatusOK, gin.H{"participant": p})
+}
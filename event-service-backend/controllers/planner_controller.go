@@ -0,0 +1,789 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAuditEntries caps one page of the audit trail.
+const maxAuditEntries = 200
+
+// plannerBooking is the payload of a booking a planner makes for a client.
+type plannerBooking struct {
+	VendorID   int      `json:"vendor_id"`
+	EventDate  string   `json:"event_date"` // YYYY-MM-DD; defaults to the event's first day
+	TotalPrice *float64 `json:"total_price"`
+	Notes      string   `json:"notes"`
+}
+
+// plannerSave is the payload of a vendor a planner saves for a client.
+type plannerSave struct {
+	VendorID int     `json:"vendor_id"`
+	Notes    *string `json:"notes"`
+}
+
+// bookForClient books a vendor for the event's owner. Vendors confirm it as
+// any other booking.
+func bookForClient(tx *sql.Tx, e models.Event, b plannerBooking) (int, error) {
+	var id int
+	err := tx.QueryRow(`
+		INSERT INTO bookings (user_id, vendor_id, event_date, status, notes, created_at, updated_at, event_id, total_price)
+		VALUES ($1, $2, $3, 'pending', $4, NOW(), NOW(), $5, $6) RETURNING id
+	`, e.UserID, b.VendorID, b.EventDate, b.Notes, e.ID, b.TotalPrice).Scan(&id)
+	return id, err
+}
+
+// saveForClient saves a vendor for the event's owner, into the event's
+// shortlist when it has one. A vendor the client already saved keeps its
+// collection.
+func saveForClient(tx *sql.Tx, e models.Event, plannerID int, s plannerSave) (int, error) {
+	var id int
+	err := tx.QueryRow(`
+		INSERT INTO saved_items (user_id, vendor_id, created_at, collection_id, notes, added_by)
+		VALUES ($1, $2, NOW(), $3, $4, $5)
+		ON CONFLICT (user_id, vendor_id) DO UPDATE SET notes = COALESCE($4, saved_items.notes)
+		RETURNING id
+	`, e.UserID, s.VendorID, e.CollectionID, s.Notes, plannerID).Scan(&id)
+	return id, err
+}
+
+// runPlannerAction does a booking or save right away when the client trusts
+// the planner, and otherwise files it for the client's approval.
+func runPlannerAction(c *gin.Context, plannerID int, e models.Event, kind string, payload any,
+	do func(tx *sql.Tx) (int, error)) {
+	requiresApproval := false
+	if e.UserID != plannerID {
+		if err := config.DB.QueryRow(`SELECT requires_approval FROM planner_clients WHERE planner_id=$1 AND client_id=$2`,
+			plannerID, e.UserID).Scan(&requiresApproval); err != nil {
+			log.Printf("runPlannerAction link error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+	}
+
+	raw, _ := json.Marshal(payload)
+	if requiresApproval {
+		r := models.PlannerRequest{PlannerID: plannerID, ClientID: e.UserID, EventID: &e.ID, Kind: kind,
+			Payload: raw, Status: "pending"}
+		err := config.DB.QueryRow(`
+			INSERT INTO planner_requests (planner_id, client_id, event_id, kind, payload)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at
+		`, r.PlannerID, r.ClientID, r.EventID, r.Kind, string(raw)).Scan(&r.ID, &r.CreatedAt)
+		if err != nil {
+			log.Printf("runPlannerAction request error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not file request"})
+			return
+		}
+		what := "a booking"
+		if kind == "save_vendor" {
+			what = "a vendor to save"
+		}
+		go utils.NotifyUser(e.UserID, "Approval needed",
+			userDisplayName(plannerID)+" suggested "+what+" for "+e.Name, map[string]string{
+				"type":       "planner_request",
+				"request_id": strconv.Itoa(r.ID),
+			})
+		utils.RecordAudit(plannerID, e.UserID, &e.ID, "request "+kind, map[string]any{"request_id": r.ID})
+		c.JSON(http.StatusAccepted, gin.H{"request": r})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	defer tx.Rollback()
+	id, err := do(tx)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("runPlannerAction %s error: %v", kind, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete " + strings.ReplaceAll(kind, "_", " ")})
+		return
+	}
+	utils.RecordAudit(plannerID, e.UserID, &e.ID, kind, map[string]any{"id": id, "payload": json.RawMessage(raw)})
+	c.JSON(http.StatusCreated, gin.H{"id": id, "kind": kind})
+}
+
+// vendorExists writes a 400 for an unknown vendor and returns false.
+func vendorExists(c *gin.Context, vendorID int) bool {
+	var exists bool
+	if err := config.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM vendors WHERE id=$1)`, vendorID).Scan(&exists); err != nil || !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor_id"})
+		return false
+	}
+	return true
+}
+
+// clientParam reads :client_id.
+func clientParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("client_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid client id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// plannerParam reads :planner_id.
+func plannerParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("planner_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid planner id"})
+		return 0, false
+	}
+	return id, true
+}
+
+const plannerClientSelect = `
+	SELECT pc.planner_id, COALESCE(p.full_name, ''), pc.client_id, COALESCE(u.full_name, ''), COALESCE(u.email, ''),
+	       pc.status, pc.requires_approval, pc.created_at, pc.accepted_at
+	FROM planner_clients pc
+	JOIN users p ON p.id = pc.planner_id
+	JOIN users u ON u.id = pc.client_id
+`
+
+func queryPlannerClients(where string, args ...any) ([]models.PlannerClient, error) {
+	rows, err := config.DB.Query(plannerClientSelect+where+` ORDER BY pc.status, pc.created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	links := []models.PlannerClient{}
+	for rows.Next() {
+		var l models.PlannerClient
+		if err := rows.Scan(&l.PlannerID, &l.PlannerName, &l.ClientID, &l.ClientName, &l.ClientEmail,
+			&l.Status, &l.RequiresApproval, &l.CreatedAt, &l.AcceptedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// POST /planner/clients
+// body: { "email": "client@example.com" }
+// Invites an existing user to have the planner plan for them.
+func InvitePlannerClient(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var input struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Email) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+
+	var clientID int
+	err := config.DB.QueryRow(`SELECT id FROM users WHERE LOWER(email)=LOWER($1)`, strings.TrimSpace(input.Email)).Scan(&clientID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no user with that email"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	if clientID == plannerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "you cannot be your own client"})
+		return
+	}
+
+	res, err := config.DB.Exec(`
+		INSERT INTO planner_clients (planner_id, client_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`, plannerID, clientID)
+	if err != nil {
+		log.Printf("InvitePlannerClient error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not invite client"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "client already invited"})
+		return
+	}
+
+	go utils.NotifyUser(clientID, "Planner invitation",
+		userDisplayName(plannerID)+" would like to plan your events", map[string]string{
+			"type":       "planner_invite",
+			"planner_id": strconv.Itoa(plannerID),
+		})
+	utils.RecordAudit(plannerID, clientID, nil, "invite client", nil)
+
+	links, err := queryPlannerClients(`WHERE pc.planner_id=$1 AND pc.client_id=$2`, plannerID, clientID)
+	if err != nil || len(links) == 0 {
+		c.JSON(http.StatusCreated, gin.H{"message": "client invited"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"client": links[0]})
+}
+
+// GET /planner/clients
+func GetPlannerClients(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	links, err := queryPlannerClients(`WHERE pc.planner_id=$1`, plannerID)
+	if err != nil {
+		log.Printf("GetPlannerClients error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch clients"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"clients": links})
+}
+
+// DELETE /planner/clients/:client_id
+// Drops the client; their events stay theirs, without a planner.
+func RemovePlannerClient(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	clientID, ok := clientParam(c)
+	if !ok {
+		return
+	}
+	if !unlinkPlanner(c, plannerID, clientID) {
+		return
+	}
+	utils.RecordAudit(plannerID, clientID, nil, "remove client", nil)
+	c.JSON(http.StatusOK, gin.H{"message": "client removed"})
+}
+
+// unlinkPlanner ends a planner-client link and takes the planner off the
+// client's events and pending requests. It writes the error response itself.
+func unlinkPlanner(c *gin.Context, plannerID, clientID int) bool {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return false
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM planner_clients WHERE planner_id=$1 AND client_id=$2`, plannerID, clientID)
+	if err != nil {
+		log.Printf("unlinkPlanner error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return false
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "link not found"})
+		return false
+	}
+	if _, err := tx.Exec(`UPDATE events SET planner_id=NULL, updated_at=NOW() WHERE planner_id=$1 AND user_id=$2`,
+		plannerID, clientID); err != nil {
+		log.Printf("unlinkPlanner events error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return false
+	}
+	if _, err := tx.Exec(`
+		UPDATE planner_requests SET status='rejected', decided_at=NOW()
+		WHERE planner_id=$1 AND client_id=$2 AND status='pending'
+	`, plannerID, clientID); err != nil {
+		log.Printf("unlinkPlanner requests error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return false
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return false
+	}
+	return true
+}
+
+// POST /planner/clients/:client_id/events
+// body: as POST /events. The client owns the event; the planner manages it.
+func CreateClientEvent(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	clientID, ok := clientParam(c)
+	if !ok {
+		return
+	}
+	var active bool
+	config.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM planner_clients WHERE planner_id=$1 AND client_id=$2 AND status='active')`,
+		plannerID, clientID).Scan(&active)
+	if !active {
+		c.JSON(http.StatusNotFound, gin.H{"error": "client not found"})
+		return
+	}
+	createEvent(c, clientID, &plannerID)
+}
+
+// GET /planner/dashboard
+// Every event the planner manages with where it stands: upcoming events
+// first, soonest first.
+func GetPlannerDashboard(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT `+eventColumns+`,
+		       (SELECT COALESCE(full_name, '') FROM users u WHERE u.id = events.user_id),
+		       (SELECT COUNT(*) FROM bookings b WHERE b.event_id = events.id AND b.status = 'pending'),
+		       (SELECT COUNT(*) FROM bookings b WHERE b.event_id = events.id AND b.status IN ('confirmed', 'completed')),
+		       (SELECT COUNT(*) FROM event_tasks t WHERE t.event_id = events.id AND NOT t.done AND t.due_date < CURRENT_DATE),
+		       (SELECT COUNT(*) FROM event_tasks t WHERE t.event_id = events.id AND NOT t.done),
+		       (SELECT COALESCE(SUM(1 + g.plus_ones), 0) FROM event_guests g
+		         WHERE g.event_id = events.id AND g.rsvp_status = 'attending'),
+		       (SELECT COALESCE(SUM(b.total_price), 0) FROM bookings b
+		         WHERE b.event_id = events.id AND b.status IN ('pending', 'confirmed', 'completed'))::float8
+		       + (SELECT COALESCE(SUM(x.amount), 0) FROM event_expenses x WHERE x.event_id = events.id)::float8,
+		       (SELECT COUNT(*) FROM planner_requests r WHERE r.event_id = events.id AND r.status = 'pending')
+		FROM events
+		WHERE planner_id = $1 AND EXISTS (
+			SELECT 1 FROM planner_clients pc
+			WHERE pc.planner_id = $1 AND pc.client_id = events.user_id AND pc.status = 'active'
+		)
+		ORDER BY end_date < CURRENT_DATE, CASE WHEN end_date >= CURRENT_DATE THEN start_date END,
+		         start_date DESC, id
+	`, plannerID)
+	if err != nil {
+		log.Printf("GetPlannerDashboard query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch dashboard"})
+		return
+	}
+	defer rows.Close()
+
+	events := []models.PlannerEventSummary{}
+	for rows.Next() {
+		var s models.PlannerEventSummary
+		e := &s.Event
+		if err := rows.Scan(&e.ID, &e.UserID, &e.PlannerID, &e.Name, &e.Type, &e.StartDate, &e.EndDate, &e.City,
+			&e.GuestCount, &e.Budget, &e.CollectionID, &e.Status, &e.CreatedAt, &e.UpdatedAt,
+			&s.ClientName, &s.PendingBookings, &s.ConfirmedBookings, &s.OverdueTasks, &s.OpenTasks,
+			&s.AttendingPeople, &s.Committed, &s.PendingRequests); err != nil {
+			log.Printf("GetPlannerDashboard scan error: %v", err)
+			continue
+		}
+		s.DaysUntil = daysUntil(e.StartDate)
+		if e.Budget != nil {
+			left := *e.Budget - s.Committed
+			s.BudgetLeft = &left
+		}
+		events = append(events, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// POST /planner/events/:id/bookings
+// body: { "vendor_id": 7, "event_date": "2025-12-31", "total_price": 50000, "notes": "..." }
+// Books right away unless the client asked to approve bookings first; then
+// answers 202 with the request waiting for them.
+func CreatePlannerBooking(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, plannerID)
+	if !ok {
+		return
+	}
+	var b plannerBooking
+	if err := c.ShouldBindJSON(&b); err != nil || b.VendorID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vendor_id is required"})
+		return
+	}
+	if b.EventDate = strings.TrimSpace(b.EventDate); b.EventDate == "" {
+		b.EventDate = e.StartDate
+	} else if _, err := time.Parse(time.DateOnly, b.EventDate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event_date must be a date like 2025-12-31"})
+		return
+	}
+	if b.TotalPrice != nil && *b.TotalPrice < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "total_price must not be negative"})
+		return
+	}
+	if !vendorExists(c, b.VendorID) {
+		return
+	}
+
+	runPlannerAction(c, plannerID, e, "booking", b, func(tx *sql.Tx) (int, error) {
+		return bookForClient(tx, e, b)
+	})
+}
+
+// POST /planner/events/:id/saved-vendors
+// body: { "vendor_id": 7, "notes": "..." }
+// Saves into the event's shortlist, with the same approval rule as bookings.
+func CreatePlannerSavedVendor(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, plannerID)
+	if !ok {
+		return
+	}
+	var s plannerSave
+	if err := c.ShouldBindJSON(&s); err != nil || s.VendorID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vendor_id is required"})
+		return
+	}
+	if !vendorExists(c, s.VendorID) {
+		return
+	}
+
+	runPlannerAction(c, plannerID, e, "save_vendor", s, func(tx *sql.Tx) (int, error) {
+		return saveForClient(tx, e, plannerID, s)
+	})
+}
+
+const plannerRequestSelect = `
+	SELECT r.id, r.planner_id, COALESCE(p.full_name, ''), r.client_id, r.event_id, r.kind, r.payload, r.status,
+	       r.result_id, r.created_at, r.decided_at
+	FROM planner_requests r
+	JOIN users p ON p.id = r.planner_id
+`
+
+func scanPlannerRequest(scanner interface{ Scan(...any) error }, r *models.PlannerRequest) error {
+	var payload []byte
+	err := scanner.Scan(&r.ID, &r.PlannerID, &r.PlannerName, &r.ClientID, &r.EventID, &r.Kind, &payload, &r.Status,
+		&r.ResultID, &r.CreatedAt, &r.DecidedAt)
+	r.Payload = payload
+	return err
+}
+
+// getPlannerRequests lists requests for one side of the link; ?status=
+// narrows them down.
+func getPlannerRequests(c *gin.Context, column string, userID int) {
+	args := utils.SQLArgs{userID}
+	where := ` WHERE r.` + column + `=$1`
+	if status := c.Query("status"); status != "" {
+		where += ` AND r.status=` + args.Add(status)
+	}
+	rows, err := config.DB.Query(plannerRequestSelect+where+` ORDER BY r.created_at DESC, r.id DESC`, args...)
+	if err != nil {
+		log.Printf("getPlannerRequests query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch requests"})
+		return
+	}
+	defer rows.Close()
+
+	requests := []models.PlannerRequest{}
+	for rows.Next() {
+		var r models.PlannerRequest
+		if err := scanPlannerRequest(rows, &r); err != nil {
+			log.Printf("getPlannerRequests scan error: %v", err)
+			continue
+		}
+		requests = append(requests, r)
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": requests})
+}
+
+// GET /planner/requests
+// What the planner is waiting on; ?status=pending|approved|rejected.
+func GetPlannerSentRequests(c *gin.Context) {
+	plannerID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	getPlannerRequests(c, "planner_id", plannerID)
+}
+
+// GET /planners
+// The user's planners, including invitations still waiting for an answer.
+func GetMyPlanners(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	links, err := queryPlannerClients(`WHERE pc.client_id=$1`, userID)
+	if err != nil {
+		log.Printf("GetMyPlanners error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch planners"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"planners": links})
+}
+
+// POST /planners/:planner_id/accept
+// body (optional): { "requires_approval": false }
+// Approval stays on unless the client turns it off.
+func AcceptPlanner(c *gin.Context) {
+	updatePlanner(c, true)
+}
+
+// PATCH /planners/:planner_id
+// body: { "requires_approval": bool }
+func UpdatePlanner(c *gin.Context) {
+	updatePlanner(c, false)
+}
+
+func updatePlanner(c *gin.Context, accept bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	plannerID, ok := plannerParam(c)
+	if !ok {
+		return
+	}
+	var input struct {
+		RequiresApproval *bool `json:"requires_approval"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil && !(accept && c.Request.ContentLength == 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if !accept && input.RequiresApproval == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "requires_approval is required"})
+		return
+	}
+
+	status := "active"
+	if !accept {
+		status = ""
+	}
+	res, err := config.DB.Exec(`
+		UPDATE planner_clients SET
+			status = COALESCE(NULLIF($3, ''), status),
+			accepted_at = CASE WHEN $3 <> '' AND status = 'pending' THEN NOW() ELSE accepted_at END,
+			requires_approval = COALESCE($4, requires_approval)
+		WHERE planner_id=$1 AND client_id=$2 AND (status = 'active' OR $3 <> '')
+	`, plannerID, userID, status, input.RequiresApproval)
+	if err != nil {
+		log.Printf("updatePlanner error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update planner"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "planner not found"})
+		return
+	}
+
+	action := "update planner"
+	if accept {
+		action = "accept planner"
+		go utils.NotifyUser(plannerID, "Invitation accepted",
+			userDisplayName(userID)+" accepted you as their planner", map[string]string{
+				"type":      "planner_accepted",
+				"client_id": strconv.Itoa(userID),
+			})
+	}
+	utils.RecordAudit(userID, userID, nil, action, map[string]any{"planner_id": plannerID, "requires_approval": input.RequiresApproval})
+
+	links, err := queryPlannerClients(`WHERE pc.planner_id=$1 AND pc.client_id=$2`, plannerID, userID)
+	if err != nil || len(links) == 0 {
+		c.JSON(http.StatusOK, gin.H{"message": "planner updated"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"planner": links[0]})
+}
+
+// DELETE /planners/:planner_id
+// Declines an invitation or lets a planner go.
+func RemovePlanner(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	plannerID, ok := plannerParam(c)
+	if !ok {
+		return
+	}
+	if !unlinkPlanner(c, plannerID, userID) {
+		return
+	}
+	utils.RecordAudit(userID, userID, nil, "remove planner", map[string]any{"planner_id": plannerID})
+	c.JSON(http.StatusOK, gin.H{"message": "planner removed"})
+}
+
+// GET /planners/requests
+// Bookings and saves planners asked to make; ?status=pending for the ones
+// waiting.
+func GetPlannerRequests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	getPlannerRequests(c, "client_id", userID)
+}
+
+// POST /planners/requests/:request_id/approve
+// Makes the booking or save as if the planner had been trusted.
+func ApprovePlannerRequest(c *gin.Context) {
+	decidePlannerRequest(c, true)
+}
+
+// POST /planners/requests/:request_id/reject
+func RejectPlannerRequest(c *gin.Context) {
+	decidePlannerRequest(c, false)
+}
+
+func decidePlannerRequest(c *gin.Context, approve bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	requestID, err := strconv.Atoi(c.Param("request_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request id"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var r models.PlannerRequest
+	err = scanPlannerRequest(tx.QueryRow(plannerRequestSelect+` WHERE r.id=$1 AND r.client_id=$2 FOR UPDATE OF r`,
+		requestID, userID), &r)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "request not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("decidePlannerRequest load error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	if r.Status != "pending" {
+		c.JSON(http.StatusConflict, gin.H{"error": "request already " + r.Status})
+		return
+	}
+
+	r.Status = "rejected"
+	if approve {
+		r.Status = "approved"
+		if r.EventID == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "the event is gone"})
+			return
+		}
+		var e models.Event
+		if err := scanEvent(tx.QueryRow(eventSelect+` WHERE id=$1`, *r.EventID), &e); err != nil {
+			log.Printf("decidePlannerRequest event error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+		var resultID int
+		switch r.Kind {
+		case "booking":
+			var b plannerBooking
+			if err = json.Unmarshal(r.Payload, &b); err == nil {
+				resultID, err = bookForClient(tx, e, b)
+			}
+		case "save_vendor":
+			var s plannerSave
+			if err = json.Unmarshal(r.Payload, &s); err == nil {
+				resultID, err = saveForClient(tx, e, r.PlannerID, s)
+			}
+		}
+		if err != nil {
+			log.Printf("decidePlannerRequest %s error: %v", r.Kind, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete request"})
+			return
+		}
+		r.ResultID = &resultID
+	}
+
+	if err := tx.QueryRow(`
+		UPDATE planner_requests SET status=$2, result_id=$3, decided_at=NOW() WHERE id=$1 RETURNING decided_at
+	`, r.ID, r.Status, r.ResultID).Scan(&r.DecidedAt); err != nil {
+		log.Printf("decidePlannerRequest update error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+
+	go utils.NotifyUser(r.PlannerID, "Request "+r.Status,
+		userDisplayName(userID)+" "+r.Status+" your "+strings.ReplaceAll(r.Kind, "_", " ")+" request", map[string]string{
+			"type":       "planner_request_" + r.Status,
+			"request_id": strconv.Itoa(r.ID),
+		})
+	utils.RecordAudit(userID, userID, r.EventID, r.Status+" "+r.Kind, map[string]any{"request_id": r.ID, "result_id": r.ResultID})
+
+	c.JSON(http.StatusOK, gin.H{"request": r})
+}
+
+// queryAudit lists the newest audit entries matching where.
+func queryAudit(where string, args ...any) ([]models.AuditEntry, error) {
+	rows, err := config.DB.Query(`
+		SELECT a.id, a.actor_id, COALESCE(u.full_name, ''), a.client_id, a.event_id, a.action,
+		       COALESCE(a.details, '{}'::jsonb), a.created_at
+		FROM audit_log a
+		LEFT JOIN users u ON u.id = a.actor_id
+		`+where+`
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT `+strconv.Itoa(maxAuditEntries), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var a models.AuditEntry
+		var details []byte
+		if err := rows.Scan(&a.ID, &a.ActorID, &a.ActorName, &a.ClientID, &a.EventID, &a.Action, &details, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.Details = details
+		entries = append(entries, a)
+	}
+	return entries, rows.Err()
+}
+
+// GET /events/:id/audit
+// Who did what to the event, newest first.
+func GetEventAudit(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	e, ok := ownedEvent(c, userID)
+	if !ok {
+		return
+	}
+	entries, err := queryAudit(`WHERE a.event_id=$1`, e.ID)
+	if err != nil {
+		log.Printf("GetEventAudit error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch audit trail"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"audit": entries})
+}
+
+// GET /planners/audit
+// Everything done on the user's data, by them or their planners.
+func GetMyAudit(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	entries, err := queryAudit(`WHERE a.client_id=$1`, userID)
+	if err != nil {
+		log.Printf("GetMyAudit error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch audit trail"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"audit": entries})
+}
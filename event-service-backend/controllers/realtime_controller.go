@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"io"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/realtime"
+	"github.com/gin-gonic/gin"
+)
+
+// realtimeHeartbeat keeps idle streams from being cut by proxies.
+const realtimeHeartbeat = 25 * time.Second
+
+// GET /realtime/stream
+// Server-Sent Events for the signed-in user. Event names:
+//   - booking_created, booking_status: { booking_id, vendor_id, event_id, status, previous_status }
+//   - message: { conversation_id, message_id, sender_id, preview, attachments, created_at }
+//   - messages_read: { conversation_id, user_id, last_read_message_id, unread_count }
+//   - notification: { title, body, data }, a copy of every push sent to the user
+//   - resync: events may have been missed; refetch what is on screen
+//
+// The stream opens with "ready" and sends "ping" while idle.
+func StreamRealtime(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	events, unsubscribe := realtime.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(realtimeHeartbeat)
+	defer heartbeat.Stop()
+
+	c.SSEvent("ready", gin.H{"user_id": userID})
+	c.Writer.Flush()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case e := <-events:
+			c.SSEvent(e.Type, e.Data)
+		case t := <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"at": t.UTC()})
+		}
+		return true
+	})
+}
@@ -9,11 +9,15 @@ import (
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/dharmaseervi/event-service-backend/config"
 	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
 // POST /saved-vendors
-// body: { "vendor_id": number }
+// body: { "vendor_id": number, "collection_id": number, "notes": string, "tags": [string] }
+// Only vendor_id is required; without collection_id the vendor goes to the
+// default collection (or stays where it is when already saved).
 func SaveVendor(c *gin.Context) {
 	// 1) Auth: clerk -> local user id
 	claims, ok := clerk.SessionClaimsFromContext(c.Request.Context())
@@ -35,15 +39,33 @@ func SaveVendor(c *gin.Context) {
 	}
 
 	print("local ID: ", localUserID, "\n")
-	// 2) Parse payload (only vendor_id)
+	// 2) Parse payload
 	var input struct {
-		VendorID int64 `json:"vendor_id"`
+		VendorID     int64    `json:"vendor_id"`
+		CollectionID *int     `json:"collection_id"`
+		Notes        *string  `json:"notes"`
+		Tags         []string `json:"tags"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil || input.VendorID == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "vendor_id is required"})
 		return
 	}
 
+	var collectionID *int
+	if input.CollectionID != nil {
+		if collectionID, ok = ownedCollection(c, int(localUserID), *input.CollectionID); !ok {
+			return
+		}
+	}
+	var tags pq.StringArray
+	if input.Tags != nil {
+		var err error
+		if tags, err = normalizeTags(input.Tags); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	fmt.Println("VendorID:", input.VendorID)
 	// (Optional) validate vendor exists
 	var exists bool
@@ -55,13 +77,18 @@ func SaveVendor(c *gin.Context) {
 	// 3) Insert (idempotent thanks to UNIQUE(user_id, vendor_id))
 	var id int64
 	var createdAt time.Time
+	var savedIn int
 
 	err := config.DB.QueryRow(`
-		INSERT INTO saved_items (user_id, vendor_id, created_at)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (user_id, vendor_id) DO UPDATE SET created_at = EXCLUDED.created_at
-		RETURNING id, created_at
-	`, localUserID, input.VendorID, time.Now()).Scan(&id, &createdAt)
+		INSERT INTO saved_items (user_id, vendor_id, created_at, collection_id, notes, tags, added_by)
+		VALUES ($1, $2, $3, $5, $6, COALESCE($7, '{}'), $1)
+		ON CONFLICT (user_id, vendor_id) DO UPDATE SET
+			created_at = EXCLUDED.created_at,
+			collection_id = CASE WHEN $4 THEN EXCLUDED.collection_id ELSE saved_items.collection_id END,
+			notes = COALESCE($6, saved_items.notes),
+			tags = COALESCE($7, saved_items.tags)
+		RETURNING id, created_at, COALESCE(collection_id, 0)
+	`, localUserID, input.VendorID, time.Now(), input.CollectionID != nil, collectionID, input.Notes, tags).Scan(&id, &createdAt, &savedIn)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save vendor"})
@@ -70,10 +97,11 @@ func SaveVendor(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id":         id,
-		"user_id":    localUserID,
-		"vendor_id":  input.VendorID,
-		"created_at": createdAt,
+		"id":            id,
+		"user_id":       localUserID,
+		"vendor_id":     input.VendorID,
+		"collection_id": savedIn,
+		"created_at":    createdAt,
 	})
 }
 
@@ -112,6 +140,7 @@ func UnsaveVendor(c *gin.Context) {
 }
 
 // GET /saved-vendors/me
+// Lists the default collection; see GetSavedCollectionVendors for the others.
 func GetMySavedVendors(c *gin.Context) {
 	claims, ok := clerk.SessionClaimsFromContext(c.Request.Context())
 	if !ok {
@@ -130,30 +159,48 @@ func GetMySavedVendors(c *gin.Context) {
 		return
 	}
 
+	args := utils.SQLArgs{localUserID}
+	sorts, err := vendorSorts(c, "v.", &args)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// newest means most recently saved, not most recently listed
+	sorts[utils.SortNewest] = utils.SortKey{Expr: "sv.created_at", Desc: true, Cast: "timestamptz"}
+	page, err := utils.ParsePage(c, sorts, utils.SortNewest, "sv.id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	rows, err := config.DB.Query(`
-		SELECT v.id, v.vendor_id, v.title, v.description, v.category, v.price_range,
-		       v.location, v.photos, v.rating, v.featured, v.created_at, v.updated_at
+		SELECT sv.id, v.id, v.vendor_id, v.title, v.description, v.category, v.price_range,
+		       v.location, v.photos, v.rating, v.featured, v.created_at, v.updated_at, `+page.SortValue()+`
 		FROM saved_items sv
 		JOIN vendors v ON sv.vendor_id = v.id
-		WHERE sv.user_id = $1
-		ORDER BY sv.created_at DESC
-	`, localUserID)
+		WHERE sv.user_id = $1 AND sv.collection_id IS NULL AND `+page.Where(args.Add)+`
+		ORDER BY `+page.OrderBy()+`
+		LIMIT `+args.Add(page.FetchLimit()), args...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed"})
 		return
 	}
 	defer rows.Close()
 
-	var vendors []models.VendorListing
+	vendors := []models.VendorListing{}
 	for rows.Next() {
 		var v models.VendorListing
+		var savedID int
+		var sortValue string
 		if err := rows.Scan(
-			&v.ID, &v.VendorID, &v.Title, &v.Description, &v.Category, &v.PriceRange,
-			&v.Location, &v.Photos, &v.Rating, &v.Featured, &v.CreatedAt, &v.UpdatedAt,
+			&savedID, &v.ID, &v.VendorID, &v.Title, &v.Description, &v.Category, &v.PriceRange,
+			&v.Location, &v.Photos, &v.Rating, &v.Featured, &v.CreatedAt, &v.UpdatedAt, &sortValue,
 		); err == nil {
+			page.Track(sortValue, savedID)
 			vendors = append(vendors, v)
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"vendors": vendors})
+	n, next := page.Done()
+	utils.RespondWithPage(c, vendors[:n], &page, next)
 }
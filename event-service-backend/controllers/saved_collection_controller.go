@@ -0,0 +1,461 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultCollectionName = "Saved"
+	maxSavedVendorTags    = 20
+)
+
+// Roles on a collection, weakest first. Viewers can read, comment and
+// vote; editors can also add, remove and reorder vendors; only the owner
+// can rename, delete or share it.
+const (
+	collectionViewer = "viewer"
+	collectionEditor = "editor"
+	collectionOwner  = "owner"
+)
+
+var collectionRoleRank = map[string]int{collectionViewer: 1, collectionEditor: 2, collectionOwner: 3}
+
+// collectionAccess is a collection as seen by the caller
+type collectionAccess struct {
+	ID      *int // nil for the default collection
+	OwnerID int
+	Name    string
+	Role    string
+}
+
+// savedCollectionParam reads :id and checks the caller has at least the
+// role need on it. 0 is the caller's own default collection. It writes the
+// error response itself and returns ok=false when the caller should stop.
+func savedCollectionParam(c *gin.Context, userID int, need string) (collectionAccess, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return collectionAccess{}, false
+	}
+	if id == 0 {
+		return collectionAccess{OwnerID: userID, Name: defaultCollectionName, Role: collectionOwner}, true
+	}
+
+	a := collectionAccess{ID: &id}
+	var role sql.NullString
+	err = config.DB.QueryRow(`
+		SELECT sc.user_id, sc.name, CASE WHEN sc.user_id = $2 THEN 'owner' ELSE m.role END
+		FROM saved_collections sc
+		LEFT JOIN saved_collection_members m ON m.collection_id = sc.id AND m.user_id = $2
+		WHERE sc.id = $1
+	`, id, userID).Scan(&a.OwnerID, &a.Name, &role)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return a, false
+	}
+	if err == sql.ErrNoRows || !role.Valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+		return a, false
+	}
+	a.Role = role.String
+	if collectionRoleRank[a.Role] < collectionRoleRank[need] {
+		c.JSON(http.StatusForbidden, gin.H{"error": "this needs the " + need + " role on the collection"})
+		return a, false
+	}
+	return a, true
+}
+
+// ownedCollection checks that collection id (0 = default) belongs to the user.
+func ownedCollection(c *gin.Context, userID, id int) (*int, bool) {
+	if id == 0 {
+		return nil, true
+	}
+	var exists bool
+	if err := config.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM saved_collections WHERE id=$1 AND user_id=$2)`,
+		id, userID).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return nil, false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+		return nil, false
+	}
+	return &id, true
+}
+
+// normalizeTags lowercases, trims and de-duplicates tags.
+func normalizeTags(tags []string) (pq.StringArray, error) {
+	out := pq.StringArray{}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	if len(out) > maxSavedVendorTags {
+		return nil, errors.New("too many tags")
+	}
+	return out, nil
+}
+
+// GET /saved-vendors/collections
+// The default collection (id 0) comes first, then the user's own by name,
+// then the ones shared with them.
+func GetMySavedCollections(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	collections := []models.SavedCollection{{ID: 0, Name: defaultCollectionName, Default: true, Role: collectionOwner, OwnerID: userID}}
+	if err := config.DB.QueryRow(`SELECT COUNT(*) FROM saved_items WHERE user_id=$1 AND collection_id IS NULL`,
+		userID).Scan(&collections[0].VendorCount); err != nil {
+		log.Printf("GetMySavedCollections count error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch collections"})
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT sc.id, sc.name, COUNT(si.id), CASE WHEN sc.user_id = $1 THEN 'owner' ELSE m.role END,
+		       sc.user_id, COALESCE(u.full_name, ''), sc.created_at, sc.updated_at
+		FROM saved_collections sc
+		JOIN users u ON u.id = sc.user_id
+		LEFT JOIN saved_collection_members m ON m.collection_id = sc.id AND m.user_id = $1
+		LEFT JOIN saved_items si ON si.collection_id = sc.id
+		WHERE sc.user_id = $1 OR m.user_id IS NOT NULL
+		GROUP BY sc.id, m.role, u.full_name
+		ORDER BY sc.user_id <> $1, lower(sc.name)
+	`, userID)
+	if err != nil {
+		log.Printf("GetMySavedCollections query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch collections"})
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sc models.SavedCollection
+		if err := rows.Scan(&sc.ID, &sc.Name, &sc.VendorCount, &sc.Role, &sc.OwnerID, &sc.OwnerName,
+			&sc.CreatedAt, &sc.UpdatedAt); err != nil {
+			log.Printf("GetMySavedCollections scan error: %v", err)
+			continue
+		}
+		collections = append(collections, sc)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"collections": collections})
+}
+
+// POST /saved-vendors/collections
+// body: { "name": "Wedding – Catering shortlist" }
+func CreateSavedCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	sc := models.SavedCollection{Name: strings.TrimSpace(input.Name), Role: collectionOwner, OwnerID: userID}
+	err := config.DB.QueryRow(`
+		INSERT INTO saved_collections (user_id, name) VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`, userID, sc.Name).Scan(&sc.ID, &sc.CreatedAt, &sc.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			c.JSON(http.StatusConflict, gin.H{"error": "you already have a collection with that name"})
+			return
+		}
+		log.Printf("CreateSavedCollection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create collection"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"collection": sc})
+}
+
+// PATCH /saved-vendors/collections/:id
+// body: { "name": string }
+func RenameSavedCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	var input struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	sc := models.SavedCollection{ID: id, Name: strings.TrimSpace(input.Name), Role: collectionOwner, OwnerID: userID}
+	err = config.DB.QueryRow(`
+		UPDATE saved_collections SET name=$3, updated_at=NOW()
+		WHERE id=$1 AND user_id=$2
+		RETURNING created_at, updated_at,
+		          (SELECT COUNT(*) FROM saved_items WHERE collection_id = $1)
+	`, id, userID, sc.Name).Scan(&sc.CreatedAt, &sc.UpdatedAt, &sc.VendorCount)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+		return
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			c.JSON(http.StatusConflict, gin.H{"error": "you already have a collection with that name"})
+			return
+		}
+		log.Printf("RenameSavedCollection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not rename collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"collection": sc})
+}
+
+// DELETE /saved-vendors/collections/:id
+// The vendors in it go back to the default collection.
+func DeleteSavedCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+
+	// positions from the deleted collection mean nothing in the default one
+	res, err := config.DB.Exec(`
+		WITH moved AS (
+			UPDATE saved_items SET collection_id = NULL, position = NULL
+			WHERE collection_id = $1 AND user_id = $2
+		)
+		DELETE FROM saved_collections WHERE id=$1 AND user_id=$2
+	`, id, userID)
+	if err != nil {
+		log.Printf("DeleteSavedCollection error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete collection"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "collection deleted"})
+}
+
+// GET /saved-vendors/collections/:id/vendors
+// In the owner's order; vendors added since the last reorder come first.
+func GetSavedCollectionVendors(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := savedCollectionParam(c, userID, collectionViewer)
+	if !ok {
+		return
+	}
+
+	items, err := collectionVendors(collection.OwnerID, collection.ID, userID)
+	if err != nil {
+		log.Printf("GetSavedCollectionVendors error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch saved vendors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"vendors": items})
+}
+
+// collectionVendors lists a collection of ownerID (nil = default) with the
+// vendors filled in; viewerID decides "voted".
+func collectionVendors(ownerID int, collectionID *int, viewerID int) ([]models.SavedVendorItem, error) {
+	rows, err := config.DB.Query(`
+		SELECT sv.id, COALESCE(sv.collection_id, 0), COALESCE(sv.notes, ''), COALESCE(sv.tags, '{}'), sv.position,
+		       sv.added_by, COALESCE(ab.full_name, ''),
+		       (SELECT COUNT(*) FROM saved_item_votes sv2 WHERE sv2.saved_item_id = sv.id),
+		       EXISTS (SELECT 1 FROM saved_item_votes sv2 WHERE sv2.saved_item_id = sv.id AND sv2.user_id = $3),
+		       (SELECT COUNT(*) FROM saved_item_comments sc WHERE sc.saved_item_id = sv.id),
+		       sv.created_at, v.id, v.vendor_id, v.title, COALESCE(v.slug, ''), v.description, v.category,
+		       v.price_range, v.location, v.photos, COALESCE(v.rating, 0)::float8, COALESCE(v.featured, false),
+		       v.created_at, v.updated_at
+		FROM saved_items sv
+		JOIN vendors v ON v.id = sv.vendor_id
+		LEFT JOIN users ab ON ab.id = sv.added_by
+		WHERE sv.user_id = $1 AND sv.collection_id IS NOT DISTINCT FROM $2
+		ORDER BY sv.position ASC NULLS FIRST, sv.created_at DESC, sv.id DESC
+	`, ownerID, collectionID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.SavedVendorItem{}
+	for rows.Next() {
+		it := models.SavedVendorItem{Vendor: &models.VendorListing{}}
+		v := it.Vendor
+		if err := rows.Scan(
+			&it.ID, &it.CollectionID, &it.Notes, &it.Tags, &it.Position,
+			&it.AddedBy, &it.AddedByName, &it.Votes, &it.Voted, &it.Comments, &it.SavedAt,
+			&v.ID, &v.VendorID, &v.Title, &v.Slug, &v.Description, &v.Category,
+			&v.PriceRange, &v.Location, &v.Photos, &v.Rating, &v.Featured,
+			&v.CreatedAt, &v.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// PUT /saved-vendors/collections/:id/order
+// body: { "vendor_ids": [3, 1, 2] } listing every vendor in the collection once
+func ReorderSavedCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := savedCollectionParam(c, userID, collectionEditor)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		VendorIDs []int64 `json:"vendor_ids"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	defer tx.Rollback()
+
+	// every vendor in the collection must be listed exactly once
+	var matches bool
+	err = tx.QueryRow(`
+		SELECT COALESCE(array_agg(vendor_id ORDER BY vendor_id), '{}') =
+		       (SELECT COALESCE(array_agg(DISTINCT x ORDER BY x), '{}') FROM unnest($3::int[]) x)
+		   AND cardinality($3::int[]) = COUNT(*)
+		FROM saved_items
+		WHERE user_id = $1 AND collection_id IS NOT DISTINCT FROM $2
+	`, collection.OwnerID, collection.ID, pq.Int64Array(input.VendorIDs)).Scan(&matches)
+	if err != nil {
+		log.Printf("ReorderSavedCollection check error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not reorder collection"})
+		return
+	}
+	if !matches {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vendor_ids must list every vendor in the collection exactly once"})
+		return
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE saved_items SET position = array_position($3::int[], vendor_id)
+		WHERE user_id = $1 AND collection_id IS NOT DISTINCT FROM $2
+	`, collection.OwnerID, collection.ID, pq.Int64Array(input.VendorIDs)); err != nil {
+		log.Printf("ReorderSavedCollection update error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not reorder collection"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not reorder collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "collection reordered"})
+}
+
+// PATCH /saved-vendors/:vendor_id
+// body: any of { "collection_id": number (0 = default), "notes": string, "tags": [string] }
+// Moving a vendor puts it at the top of the target collection.
+func UpdateSavedVendor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	vendorID, err := strconv.Atoi(c.Param("vendor_id"))
+	if err != nil || vendorID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor_id"})
+		return
+	}
+
+	var input struct {
+		CollectionID *int     `json:"collection_id"`
+		Notes        *string  `json:"notes"`
+		Tags         []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	var collectionID *int
+	if input.CollectionID != nil {
+		if collectionID, ok = ownedCollection(c, userID, *input.CollectionID); !ok {
+			return
+		}
+	}
+	var tags pq.StringArray
+	if input.Tags != nil {
+		if tags, err = normalizeTags(input.Tags); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var it models.SavedVendorItem
+	err = config.DB.QueryRow(`
+		UPDATE saved_items SET
+			collection_id = CASE WHEN $3 THEN $4 ELSE collection_id END,
+			position = CASE WHEN $3 AND collection_id IS DISTINCT FROM $4 THEN NULL ELSE position END,
+			notes = COALESCE($5, notes),
+			tags = COALESCE($6, tags)
+		WHERE user_id = $1 AND vendor_id = $2
+		RETURNING id, COALESCE(collection_id, 0), COALESCE(notes, ''), COALESCE(tags, '{}'), position, created_at
+	`, userID, vendorID, input.CollectionID != nil, collectionID, input.Notes, tags).Scan(
+		&it.ID, &it.CollectionID, &it.Notes, &it.Tags, &it.Position, &it.SavedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "vendor is not saved"})
+		return
+	}
+	if err != nil {
+		log.Printf("UpdateSavedVendor error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update saved vendor"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"saved_vendor": it})
+}
@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/search"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const savedSearchSelect = `
+	SELECT id, user_id, name, query, notify, last_run_at, created_at, updated_at
+	FROM saved_searches
+`
+
+func scanSavedSearch(scanner interface{ Scan(...any) error }, s *models.SavedSearch) error {
+	return scanner.Scan(&s.ID, &s.UserID, &s.Name, &s.Query, &s.Notify, &s.LastRunAt, &s.CreatedAt, &s.UpdatedAt)
+}
+
+// normalizeSavedQuery checks a /search/vendors query string and drops the
+// paging parameters, which make no sense to save.
+func normalizeSavedQuery(raw string) (string, error) {
+	params, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(raw), "?"))
+	if err != nil {
+		return "", errors.New("query must be a URL query string")
+	}
+	for _, key := range []string{"limit", "cursor", "sort"} {
+		params.Del(key)
+	}
+	if _, err := parseSearchQuery(params); err != nil {
+		return "", err
+	}
+	return params.Encode(), nil
+}
+
+// GET /saved-searches
+func GetMySavedSearches(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(savedSearchSelect+` WHERE user_id=$1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		log.Printf("GetMySavedSearches query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch saved searches"})
+		return
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		var s models.SavedSearch
+		if err := scanSavedSearch(rows, &s); err != nil {
+			log.Printf("GetMySavedSearches scan error: %v", err)
+			continue
+		}
+		searches = append(searches, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"saved_searches": searches})
+}
+
+// POST /saved-searches
+// body: { "name": "DJs in Pune", "query": "q=dj&city=pune", "notify": true }
+// query takes the same parameters as /search/vendors; notify defaults to true.
+func CreateSavedSearch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Name   string `json:"name"`
+		Query  string `json:"query"`
+		Notify *bool  `json:"notify"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	query, err := normalizeSavedQuery(input.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	notify := input.Notify == nil || *input.Notify
+
+	var s models.SavedSearch
+	err = config.DB.QueryRow(`
+		INSERT INTO saved_searches (user_id, name, query, notify)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, name, query, notify, last_run_at, created_at, updated_at
+	`, userID, name, query, notify).Scan(&s.ID, &s.UserID, &s.Name, &s.Query, &s.Notify, &s.LastRunAt, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		log.Printf("CreateSavedSearch insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save search"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"saved_search": s})
+}
+
+// PATCH /saved-searches/:id
+// body: any of { "name", "query", "notify" }
+func UpdateSavedSearch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var input struct {
+		Name   *string `json:"name"`
+		Query  *string `json:"query"`
+		Notify *bool   `json:"notify"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	if input.Name != nil {
+		if *input.Name = strings.TrimSpace(*input.Name); *input.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+			return
+		}
+	}
+	if input.Query != nil {
+		query, err := normalizeSavedQuery(*input.Query)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		input.Query = &query
+	}
+
+	var s models.SavedSearch
+	err = scanSavedSearch(config.DB.QueryRow(`
+		UPDATE saved_searches
+		SET name = COALESCE($3, name), query = COALESCE($4, query), notify = COALESCE($5, notify), updated_at = NOW()
+		WHERE id=$1 AND user_id=$2
+		RETURNING id, user_id, name, query, notify, last_run_at, created_at, updated_at
+	`, id, userID, input.Name, input.Query, input.Notify), &s)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "saved search not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("UpdateSavedSearch error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"saved_search": s})
+}
+
+// DELETE /saved-searches/:id
+func DeleteSavedSearch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	res, err := config.DB.Exec(`DELETE FROM saved_searches WHERE id=$1 AND user_id=$2`, id, userID)
+	if err != nil {
+		log.Printf("DeleteSavedSearch error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete saved search"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "saved search not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "saved search deleted"})
+}
+
+// RunSavedSearchAlerts re-runs every saved search with notify on and pushes
+// the owner a summary when vendors or deals matching it were added since the
+// previous run. The first run only counts matches added after the search was
+// saved.
+func RunSavedSearchAlerts(ctx context.Context) error {
+	rows, err := config.DB.QueryContext(ctx, savedSearchSelect+` WHERE notify ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	var searches []models.SavedSearch
+	for rows.Next() {
+		var s models.SavedSearch
+		if err := scanSavedSearch(rows, &s); err != nil {
+			rows.Close()
+			return err
+		}
+		searches = append(searches, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, s := range searches {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := runSavedSearchAlert(ctx, s); err != nil {
+			log.Printf("Saved search %d alert failed: %v", s.ID, err)
+		}
+	}
+	return nil
+}
+
+func runSavedSearchAlert(ctx context.Context, s models.SavedSearch) error {
+	// Postgres keeps microseconds; the hand-back below compares against it
+	runAt := time.Now().Truncate(time.Microsecond)
+	since := s.CreatedAt
+	if s.LastRunAt != nil {
+		since = *s.LastRunAt
+	}
+
+	// every instance runs this job; only the one that moves last_run_at on
+	// gets to alert
+	res, err := config.DB.ExecContext(ctx, `
+		UPDATE saved_searches SET last_run_at=$2 WHERE id=$1 AND last_run_at IS NOT DISTINCT FROM $3
+	`, s.ID, runAt, s.LastRunAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+	if err := alertSavedSearch(ctx, s, since); err != nil {
+		// hand the window back so the next run retries it
+		config.DB.ExecContext(ctx, `UPDATE saved_searches SET last_run_at=$3 WHERE id=$1 AND last_run_at=$2`,
+			s.ID, runAt, s.LastRunAt)
+		return err
+	}
+	return nil
+}
+
+// alertSavedSearch pushes the owner what matched s since the given time.
+func alertSavedSearch(ctx context.Context, s models.SavedSearch, since time.Time) error {
+	params, err := url.ParseQuery(s.Query)
+	if err != nil {
+		return err
+	}
+	q, err := parseSearchQuery(params)
+	if err != nil {
+		return err
+	}
+	ids, err := search.Vendors.MatchingIDs(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	var newVendors, newDeals int
+	err = config.DB.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM vendors WHERE id = ANY($1) AND created_at > $2),
+			(SELECT COUNT(*) FROM vendor_deals WHERE vendor_id = ANY($1) AND created_at > $2 AND end_date >= NOW())
+	`, pq.Array(ids), since).Scan(&newVendors, &newDeals)
+	if err != nil {
+		return err
+	}
+
+	if newVendors > 0 || newDeals > 0 {
+		var parts []string
+		if newVendors > 0 {
+			parts = append(parts, plural(newVendors, "new vendor"))
+		}
+		if newDeals > 0 {
+			parts = append(parts, plural(newDeals, "new deal"))
+		}
+		go utils.NotifyUser(s.UserID, "New matches for "+s.Name, strings.Join(parts, " and ")+" since you last checked", map[string]string{
+			"type":            "saved_search",
+			"saved_search_id": strconv.Itoa(s.ID),
+			"query":           s.Query,
+		})
+	}
+	return nil
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// savedVendorDigestInterval is the least time between two digests to the
+// same user, so a busy day still ends up as one push.
+const savedVendorDigestInterval = 24 * time.Hour
+
+// GET /saved-vendors/alerts
+func GetSavedVendorAlerts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var a models.SavedVendorAlerts
+	err := config.DB.QueryRow(`SELECT new_deals, price_drops, last_digest_at FROM saved_vendor_alerts WHERE user_id=$1`,
+		userID).Scan(&a.NewDeals, &a.PriceDrops, &a.LastDigestAt)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("GetSavedVendorAlerts error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch alert settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alerts": a})
+}
+
+// PUT /saved-vendors/alerts
+// body: { "new_deals": bool, "price_drops": bool }
+// Turning alerts on starts from now; earlier changes are not sent.
+func UpdateSavedVendorAlerts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		NewDeals   *bool `json:"new_deals"`
+		PriceDrops *bool `json:"price_drops"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.NewDeals == nil || input.PriceDrops == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "new_deals and price_drops are required"})
+		return
+	}
+
+	var a models.SavedVendorAlerts
+	err := config.DB.QueryRow(`
+		INSERT INTO saved_vendor_alerts (user_id, new_deals, price_drops)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			new_deals = EXCLUDED.new_deals,
+			price_drops = EXCLUDED.price_drops,
+			last_digest_at = CASE WHEN saved_vendor_alerts.new_deals OR saved_vendor_alerts.price_drops
+			                      THEN saved_vendor_alerts.last_digest_at ELSE NOW() END,
+			updated_at = NOW()
+		RETURNING new_deals, price_drops, last_digest_at
+	`, userID, *input.NewDeals, *input.PriceDrops).Scan(&a.NewDeals, &a.PriceDrops, &a.LastDigestAt)
+	if err != nil {
+		log.Printf("UpdateSavedVendorAlerts error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save alert settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alerts": a})
+}
+
+// savedVendorDigestUser is an opted-in user due a digest
+type savedVendorDigestUser struct {
+	userID     int
+	newDeals   bool
+	priceDrops bool
+	since      time.Time
+}
+
+// RunSavedVendorDigests sends each opted-in user one push summing up the
+// new deals and price drops on their saved vendors since their last
+// digest. Users get at most one digest a day and none when nothing changed.
+func RunSavedVendorDigests(ctx context.Context) error {
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT user_id, new_deals, price_drops, last_digest_at
+		FROM saved_vendor_alerts
+		WHERE (new_deals OR price_drops) AND last_digest_at <= $1
+		ORDER BY user_id
+	`, time.Now().Add(-savedVendorDigestInterval))
+	if err != nil {
+		return err
+	}
+	var users []savedVendorDigestUser
+	for rows.Next() {
+		var u savedVendorDigestUser
+		if err := rows.Scan(&u.userID, &u.newDeals, &u.priceDrops, &u.since); err != nil {
+			rows.Close()
+			return err
+		}
+		users = append(users, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := runSavedVendorDigest(ctx, u); err != nil {
+			log.Printf("Saved vendor digest for user %d failed: %v", u.userID, err)
+		}
+	}
+	return nil
+}
+
+func runSavedVendorDigest(ctx context.Context, u savedVendorDigestUser) error {
+	runAt := time.Now()
+
+	// A vendor counts as a price drop when its starting price is now below
+	// what it was at the previous digest, however many edits happened since.
+	rows, err := config.DB.QueryContext(ctx, `
+		WITH saved AS (
+			SELECT vendor_id FROM saved_items WHERE user_id = $1
+		), first_change AS (
+			SELECT DISTINCT ON (pc.vendor_id) pc.vendor_id, pc.old_price_min
+			FROM vendor_price_changes pc
+			WHERE $4 AND pc.vendor_id IN (SELECT vendor_id FROM saved) AND pc.changed_at > $2
+			ORDER BY pc.vendor_id, pc.changed_at, pc.id
+		)
+		SELECT 'deal', v.id, v.title
+		FROM vendor_deals d JOIN vendors v ON v.id = d.vendor_id
+		WHERE $3 AND d.vendor_id IN (SELECT vendor_id FROM saved) AND d.created_at > $2 AND d.end_date >= NOW()
+		UNION ALL
+		SELECT 'price', v.id, v.title
+		FROM first_change f JOIN vendors v ON v.id = f.vendor_id
+		WHERE v.price_min < f.old_price_min
+	`, u.userID, u.since, u.newDeals, u.priceDrops)
+	if err != nil {
+		return err
+	}
+	var deals, drops int
+	var vendorIDs []string
+	var titles []string
+	seen := map[int]bool{}
+	for rows.Next() {
+		var kind, title string
+		var vendorID int
+		if err := rows.Scan(&kind, &vendorID, &title); err != nil {
+			rows.Close()
+			return err
+		}
+		if kind == "deal" {
+			deals++
+		} else {
+			drops++
+		}
+		if !seen[vendorID] {
+			seen[vendorID] = true
+			vendorIDs = append(vendorIDs, strconv.Itoa(vendorID))
+			titles = append(titles, title)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// nothing to say yet: keep the window open so the next change goes out
+	// as soon as it happens
+	if deals == 0 && drops == 0 {
+		return nil
+	}
+
+	// every instance runs this job; only the one that moves the window on
+	// sends the digest
+	res, err := config.DB.ExecContext(ctx, `
+		UPDATE saved_vendor_alerts SET last_digest_at=$2 WHERE user_id=$1 AND last_digest_at=$3
+	`, u.userID, runAt, u.since)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	var parts []string
+	if deals > 0 {
+		parts = append(parts, plural(deals, "new deal"))
+	}
+	if drops > 0 {
+		parts = append(parts, plural(drops, "price drop"))
+	}
+	go utils.NotifyUser(u.userID, "Updates on your saved vendors",
+		strings.Join(parts, " and ")+" from "+joinTitles(titles), map[string]string{
+			"type":       "saved_vendor_digest",
+			"vendor_ids": strings.Join(vendorIDs, ","),
+		})
+	return nil
+}
+
+// joinTitles lists up to two names and counts the rest:
+// "A", "A and B", "A, B and 3 more".
+func joinTitles(titles []string) string {
+	switch len(titles) {
+	case 1:
+		return titles[0]
+	case 2:
+		return titles[0] + " and " + titles[1]
+	default:
+		return titles[0] + ", " + titles[1] + " and " + strconv.Itoa(len(titles)-2) + " more"
+	}
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/search"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultReportDays  = 30
+	maxReportDays      = 365
+	defaultReportLimit = 50
+)
+
+const searchIDBytes = 12
+
+// newSearchID returns a random id for a logged search
+func newSearchID() string {
+	b := make([]byte, searchIDBytes)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// isSearchID reports whether s looks like an id from newSearchID
+func isSearchID(s string) bool {
+	b, err := hex.DecodeString(s)
+	return err == nil && len(b) == searchIDBytes
+}
+
+// searchLogSalt keys the user hashes in search_logs. Without
+// SEARCH_LOG_SALT each process draws a random one, so hashes still cannot be
+// reversed but unique-user counts only hold within one process; set it so
+// they match across instances and restarts.
+var searchLogSalt = sync.OnceValue(func() []byte {
+	if salt := os.Getenv("SEARCH_LOG_SALT"); salt != "" {
+		return []byte(salt)
+	}
+	log.Printf("SEARCH_LOG_SALT is not set; using a random salt for search log user hashes")
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		log.Fatalf("could not generate search log salt: %v", err)
+	}
+	return salt
+})
+
+// anonymizedUser identifies who searched without storing who it was: a
+// keyed hash of the Clerk user, or of the client IP for anonymous visitors.
+func anonymizedUser(c *gin.Context) string {
+	subject := "ip:" + c.ClientIP()
+	if claims, ok := clerk.SessionClaimsFromContext(c.Request.Context()); ok {
+		subject = "user:" + claims.Subject
+	}
+	mac := hmac.New(sha256.New, searchLogSalt())
+	mac.Write([]byte(subject))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+func normalizeSearchQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// logSearch records one search in the background. Callers only log first
+// pages so paging through results does not count as more searches.
+func logSearch(c *gin.Context, searchID, query string, res *search.Result, started time.Time) {
+	filters := url.Values{}
+	for key, values := range c.Request.URL.Query() {
+		if key != "q" && key != "limit" && key != "search_id" {
+			filters[key] = values
+		}
+	}
+	filtersJSON, _ := json.Marshal(filters)
+	userHash := anonymizedUser(c)
+	latency := time.Since(started).Milliseconds()
+
+	go func() {
+		if _, err := config.DB.Exec(`
+			INSERT INTO search_logs (id, query, normalized_query, filters, result_count, fuzzy, latency_ms, user_hash)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, searchID, query, normalizeSearchQuery(query), filtersJSON, res.Total, res.Fuzzy,
+			latency, userHash); err != nil {
+			log.Printf("Error logging search: %v", err)
+		}
+	}()
+}
+
+// recordSearchClick logs a click-through when a vendor page is opened from
+// search results (?search_id=&position=).
+func recordSearchClick(c *gin.Context, vendorID int) {
+	searchID := c.Query("search_id")
+	if searchID == "" {
+		return
+	}
+	var position *int
+	if p, err := strconv.Atoi(c.Query("position")); err == nil && p > 0 {
+		position = &p
+	}
+
+	go func() {
+		if _, err := config.DB.Exec(`
+			INSERT INTO search_clicks (search_id, vendor_id, position)
+			SELECT id, $2, $3 FROM search_logs WHERE id = $1
+		`, searchID, vendorID, position); err != nil {
+			log.Printf("Error logging search click: %v", err)
+		}
+	}()
+}
+
+// reportWindow reads ?days= and ?limit= for the analytics reports
+func reportWindow(c *gin.Context) (days, limit int, ok bool) {
+	days, limit = defaultReportDays, defaultReportLimit
+	if raw := c.Query("days"); raw != "" {
+		d, err := strconv.Atoi(raw)
+		if err != nil || d < 1 || d > maxReportDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return 0, 0, false
+		}
+		days = d
+	}
+	if raw := c.Query("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return 0, 0, false
+		}
+		limit = min(l, 500)
+	}
+	return days, limit, true
+}
+
+// queryStats groups the search logs of the last days by normalized query.
+// having filters the groups and orderBy ranks them.
+func queryStats(days, limit int, having, orderBy string) ([]models.SearchQueryStats, error) {
+	rows, err := config.DB.Query(`
+		WITH logs AS (
+			SELECT l.id, l.normalized_query, l.result_count, l.latency_ms, l.user_hash,
+			       (SELECT COUNT(*) FROM search_clicks sc WHERE sc.search_id = l.id) AS clicks
+			FROM search_logs l
+			WHERE l.created_at > NOW() - make_interval(days => $1)
+		)
+		SELECT normalized_query, COUNT(*), COUNT(DISTINCT user_hash),
+		       COUNT(*) FILTER (WHERE result_count = 0),
+		       COALESCE(AVG(result_count), 0)::float8, COALESCE(AVG(latency_ms), 0)::float8,
+		       COALESCE(SUM(clicks), 0), COUNT(*) FILTER (WHERE clicks > 0)
+		FROM logs
+		GROUP BY normalized_query
+		HAVING `+having+`
+		ORDER BY `+orderBy+`
+		LIMIT $2
+	`, days, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.SearchQueryStats{}
+	for rows.Next() {
+		var s models.SearchQueryStats
+		if err := rows.Scan(&s.Query, &s.Searches, &s.Users, &s.ZeroResults, &s.AvgResults, &s.AvgLatencyMs,
+			&s.Clicks, &s.ClickedSearches); err != nil {
+			return nil, err
+		}
+		if s.Searches > 0 {
+			s.ClickThrough = float64(s.ClickedSearches) / float64(s.Searches)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// GET /admin/search/analytics/top-queries?days=30&limit=50
+func GetTopSearchQueries(c *gin.Context) {
+	days, limit, ok := reportWindow(c)
+	if !ok {
+		return
+	}
+	stats, err := queryStats(days, limit, "TRUE", "COUNT(*) DESC, normalized_query")
+	if err != nil {
+		log.Printf("GetTopSearchQueries error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build report"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"days": days, "queries": stats})
+}
+
+// GET /admin/search/analytics/zero-results?days=30&limit=50
+// Queries that returned nothing, most frequent first: gaps in the catalog or
+// candidates for synonyms.
+func GetZeroResultSearchQueries(c *gin.Context) {
+	days, limit, ok := reportWindow(c)
+	if !ok {
+		return
+	}
+	stats, err := queryStats(days, limit, "COUNT(*) FILTER (WHERE result_count = 0) > 0",
+		"COUNT(*) FILTER (WHERE result_count = 0) DESC, normalized_query")
+	if err != nil {
+		log.Printf("GetZeroResultSearchQueries error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build report"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"days": days, "queries": stats})
+}
+
+// GET /admin/search/analytics/click-through?days=30&limit=50
+// Overall click-through plus the queries searched most that rarely lead to
+// a vendor page.
+func GetSearchClickThrough(c *gin.Context) {
+	days, limit, ok := reportWindow(c)
+	if !ok {
+		return
+	}
+
+	var searches, clicked int
+	var avgPosition *float64
+	err := config.DB.QueryRow(`
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE EXISTS (SELECT 1 FROM search_clicks sc WHERE sc.search_id = l.id)),
+		       (SELECT AVG(sc.position)::float8 FROM search_clicks sc
+		        JOIN search_logs sl ON sl.id = sc.search_id
+		        WHERE sl.created_at > NOW() - make_interval(days => $1))
+		FROM search_logs l
+		WHERE l.created_at > NOW() - make_interval(days => $1)
+	`, days).Scan(&searches, &clicked, &avgPosition)
+	if err != nil {
+		log.Printf("GetSearchClickThrough error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build report"})
+		return
+	}
+
+	stats, err := queryStats(days, limit, "COUNT(*) FILTER (WHERE result_count > 0) > 0",
+		"COUNT(*) FILTER (WHERE clicks > 0)::float8 / COUNT(*) ASC, COUNT(*) DESC, normalized_query")
+	if err != nil {
+		log.Printf("GetSearchClickThrough error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build report"})
+		return
+	}
+
+	clickThrough := 0.0
+	if searches > 0 {
+		clickThrough = float64(clicked) / float64(searches)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"days":                 days,
+		"searches":             searches,
+		"clicked_searches":     clicked,
+		"click_through":        clickThrough,
+		"avg_click_position":   avgPosition,
+		"lowest_click_queries": stats,
+	})
+}
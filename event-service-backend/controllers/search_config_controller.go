@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/search"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// GET /admin/search/synonyms
+func GetSearchSynonyms(c *gin.Context) {
+	rows, err := config.DB.Query(`
+		SELECT id, term, synonyms, created_at, updated_at FROM search_synonyms ORDER BY term
+	`)
+	if err != nil {
+		log.Printf("GetSearchSynonyms query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch synonyms"})
+		return
+	}
+	defer rows.Close()
+
+	synonyms := []models.SearchSynonym{}
+	for rows.Next() {
+		var s models.SearchSynonym
+		if err := rows.Scan(&s.ID, &s.Term, &s.Synonyms, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			log.Printf("GetSearchSynonyms scan error: %v", err)
+			continue
+		}
+		synonyms = append(synonyms, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"synonyms": synonyms})
+}
+
+// POST /admin/search/synonyms
+// body: { "term": "dj", "synonyms": ["entertainment", "disc jockey"] }
+// Creates the entry or replaces the synonyms of an existing term.
+func UpsertSearchSynonym(c *gin.Context) {
+	var input models.SearchSynonym
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	input.Term = strings.ToLower(strings.TrimSpace(input.Term))
+	var syns pq.StringArray
+	for _, s := range input.Synonyms {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" && s != input.Term {
+			syns = append(syns, s)
+		}
+	}
+	if input.Term == "" || len(syns) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "term and at least one synonym are required"})
+		return
+	}
+	input.Synonyms = syns
+
+	err := config.DB.QueryRow(`
+		INSERT INTO search_synonyms (term, synonyms) VALUES ($1, $2)
+		ON CONFLICT (term) DO UPDATE SET synonyms = EXCLUDED.synonyms, updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`, input.Term, input.Synonyms).Scan(&input.ID, &input.CreatedAt, &input.UpdatedAt)
+	if err != nil {
+		log.Printf("UpsertSearchSynonym error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save synonym"})
+		return
+	}
+	search.InvalidateConfig()
+
+	c.JSON(http.StatusOK, gin.H{"synonym": input})
+}
+
+// DELETE /admin/search/synonyms/:id
+func DeleteSearchSynonym(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	res, err := config.DB.Exec(`DELETE FROM search_synonyms WHERE id=$1`, id)
+	if err != nil {
+		log.Printf("DeleteSearchSynonym error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete synonym"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "synonym not found"})
+		return
+	}
+	search.InvalidateConfig()
+
+	c.JSON(http.StatusOK, gin.H{"message": "synonym deleted"})
+}
+
+// GET /admin/search/settings
+func GetSearchSettings(c *gin.Context) {
+	s, err := search.LoadSettings()
+	if err != nil {
+		log.Printf("GetSearchSettings error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch settings"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"settings": s})
+}
+
+// PUT /admin/search/settings
+// body: models.SearchSettings; weights must be between 0 and 1 (ts_rank
+// rejects anything else) and boosts must not be negative
+func UpdateSearchSettings(c *gin.Context) {
+	var s models.SearchSettings
+	if err := c.ShouldBindJSON(&s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	for _, v := range []float64{s.WeightTitle, s.WeightCategory, s.WeightDescription, s.WeightLocation} {
+		if v < 0 || v > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "weights must be between 0 and 1"})
+			return
+		}
+	}
+	for _, v := range []float64{s.RatingBoost, s.FeaturedBoost} {
+		if v < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "boosts must not be negative"})
+			return
+		}
+	}
+
+	err := config.DB.QueryRow(`
+		UPDATE search_settings
+		SET weight_title=$1, weight_category=$2, weight_description=$3, weight_location=$4,
+		    rating_boost=$5, featured_boost=$6, updated_at=NOW()
+		WHERE id = 1
+		RETURNING updated_at
+	`, s.WeightTitle, s.WeightCategory, s.WeightDescription, s.WeightLocation, s.RatingBoost, s.FeaturedBoost).Scan(&s.UpdatedAt)
+	if err != nil {
+		log.Printf("UpdateSearchSettings error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save settings"})
+		return
+	}
+	search.InvalidateConfig()
+
+	c.JSON(http.StatusOK, gin.H{"settings": s})
+}
@@ -1,106 +1,188 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/dharmaseervi/event-service-backend/config"
-	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/search"
 	"github.com/dharmaseervi/event-service-backend/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 )
 
-func SearchVendors(c *gin.Context) {
-	searchQuery := strings.TrimSpace(c.Query("q"))
-	location := strings.TrimSpace(c.Query("location"))
-	category := strings.TrimSpace(c.Query("category"))
-	fromDate := strings.TrimSpace(c.Query("from_date"))
-	toDate := strings.TrimSpace(c.Query("to_date"))
-
-	if searchQuery == "" {
-		utils.RespondWithError(c, http.StatusBadRequest, "Search query is required")
-		return
+// parseSearchQuery turns /search/vendors query parameters into a search
+// query. It is shared by SearchVendors and saved searches. Errors describe
+// invalid parameters and are safe to show to the client.
+func parseSearchQuery(params url.Values) (search.Query, error) {
+	q := search.Query{
+		Text:          strings.TrimSpace(params.Get("q")),
+		Location:      strings.TrimSpace(params.Get("location")),
+		NextAvailable: params.Get("next_available") == "true",
+		Sort:          strings.TrimSpace(params.Get("sort")),
+		Cursor:        params.Get("cursor"),
+		HasDeal:       params.Get("has_deal") == "true",
 	}
 
-	vendors := []models.VendorListing{}
+	if q.Text == "" {
+		return q, errors.New("Search query is required")
+	}
+	lang, ok := normalizeLanguage(params.Get("lang"))
+	if !ok {
+		return q, errors.New("lang must be a code like en or hi")
+	}
+	q.Lang = lang
 
-	query := `
-	SELECT 
-		id, vendor_id, title, description, category, 
-		price_range, location, photos, created_at, updated_at,
-		ts_rank(search_vector, websearch_to_tsquery('english', $1)) as rank
-	FROM vendors
-	WHERE search_vector @@ websearch_to_tsquery('english', $1)
-	`
-	args := []interface{ any }{searchQuery}
-	argPos := 2
+	if err := parseSearchDates(params, &q); err != nil {
+		return q, err
+	}
 
-	if location != "" {
-		query += fmt.Sprintf(" AND location ILIKE $%d", argPos)
-		args = append(args, "%"+location+"%")
-		argPos++
+	if raw := params.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return q, errors.New("limit must be a positive number")
+		}
+		q.Limit = limit
 	}
 
-	if category != "" {
-		query += fmt.Sprintf(" AND category ILIKE $%d", argPos)
-		args = append(args, "%"+category+"%")
-		argPos++
+	q.Categories = queryList(params, "category")
+	q.Cities = queryList(params, "city")
+	q.PriceBuckets = queryList(params, "price")
+	for _, key := range q.PriceBuckets {
+		if !search.ValidPriceBucket(key) {
+			return q, errors.New("Unknown price bucket: " + key)
+		}
+	}
+	if raw := params.Get("min_rating"); raw != "" {
+		minRating, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return q, errors.New("min_rating must be a number")
+		}
+		q.MinRating = &minRating
 	}
 
-	if fromDate != "" && toDate != "" {
-		query += fmt.Sprintf(`
-			AND NOT EXISTS (
-				SELECT 1 FROM vendor_bookings vb
-				WHERE vb.vendor_id = vendors.id
-				AND vb.booked_from <= $%d
-				AND vb.booked_to >= $%d
-			)
-		`, argPos, argPos+1)
-		args = append(args, toDate, fromDate)
-		argPos += 2
+	if q.Sort == utils.SortDistance {
+		lat, errLat := strconv.ParseFloat(params.Get("lat"), 64)
+		lng, errLng := strconv.ParseFloat(params.Get("lng"), 64)
+		if errLat != nil || errLng != nil {
+			return q, errors.New("sort=distance needs numeric lat and lng")
+		}
+		q.Lat, q.Lng = &lat, &lng
 	}
 
-	query += " ORDER BY rank DESC, created_at DESC LIMIT 50"
+	return q, nil
+}
+
+// parseSearchDates reads either event_date or a from_date/to_date range,
+// all as YYYY-MM-DD.
+func parseSearchDates(params url.Values, q *search.Query) error {
+	parse := func(key string) (*time.Time, error) {
+		raw := strings.TrimSpace(params.Get(key))
+		if raw == "" {
+			return nil, nil
+		}
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date like 2025-12-31", key)
+		}
+		return &d, nil
+	}
 
-	rows, err := config.DB.Query(query, args...)
+	eventDate, err := parse("event_date")
+	if err != nil {
+		return err
+	}
+	if q.From, err = parse("from_date"); err != nil {
+		return err
+	}
+	if q.To, err = parse("to_date"); err != nil {
+		return err
+	}
+
+	switch {
+	case eventDate != nil && (q.From != nil || q.To != nil):
+		return errors.New("use either event_date or from_date and to_date, not both")
+	case eventDate != nil:
+		q.From, q.To = eventDate, eventDate
+	case (q.From == nil) != (q.To == nil):
+		return errors.New("from_date and to_date must be given together")
+	case q.From != nil && q.To.Before(*q.From):
+		return errors.New("to_date must not be before from_date")
+	}
+	return nil
+}
+
+// GET /search/vendors
+// Facet filters (all optional, combinable): category and city take
+// comma-separated lists, price takes bucket keys, min_rating a number and
+// has_deal=true. Facet counts are computed over the full matching set; each
+// facet ignores its own filter so the sidebar still shows the alternatives.
+// ?lang= (default en) picks the text search config for the query.
+// Availability: ?event_date= or ?from_date=&to_date= (YYYY-MM-DD) leave out
+// vendors that are blocked or have a confirmed booking on those days;
+// ?next_available=true adds each vendor's next free date.
+// Each search is logged for analytics; pass the returned search_id to
+// GET /vendors/:id so click-throughs are counted. Only first pages are
+// logged: send the first page's search_id along with ?cursor= and later
+// pages return it too, without it they return a null search_id.
+// Results are paged with ?limit=&cursor= and ordered by
+// ?sort=relevance|newest|rating|price|distance (distance needs lat and lng).
+func SearchVendors(c *gin.Context) {
+	started := time.Now()
+	q, err := parseSearchQuery(c.Request.URL.Query())
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := search.Vendors.Search(c.Request.Context(), q)
+	var queryErr *search.QueryError
+	if errors.As(err, &queryErr) {
+		utils.RespondWithError(c, http.StatusBadRequest, queryErr.Error())
+		return
+	}
 	if err != nil {
 		log.Printf("Error searching vendors: %v", err)
 		utils.RespondWithError(c, http.StatusInternalServerError, "Could not perform search")
 		return
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var vendor models.VendorListing
-		var photos pq.StringArray
-		var loc string
-		var rank float64
-
-		err := rows.Scan(
-			&vendor.ID,
-			&vendor.VendorID,
-			&vendor.Title,
-			&vendor.Description,
-			&vendor.Category,
-			&vendor.PriceRange,
-			&loc,
-			&photos,
-			&vendor.CreatedAt,
-			&vendor.UpdatedAt,
-			&rank,
-		)
-		if err != nil {
-			log.Printf("Error scanning result: %v", err)
-			utils.RespondWithError(c, http.StatusInternalServerError, "Failed to read results")
-			return
-		}
-		vendor.Location = loc
-		vendor.Photos = []string(photos)
-		vendors = append(vendors, vendor)
+
+	var suggestion any
+	if res.DidYouMean != "" {
+		suggestion = res.DidYouMean
+	}
+
+	var searchID any
+	if q.Cursor == "" {
+		id := newSearchID()
+		logSearch(c, id, q.Text, res, started)
+		searchID = id
+	} else if id := c.Query("search_id"); isSearchID(id) {
+		searchID = id
 	}
 
-	utils.RespondWithJSON(c, http.StatusOK, vendors)
+	utils.RespondWithPage(c, res.Vendors, &res.Page, res.NextCursor, gin.H{
+		"search_id":    searchID,
+		"total":        res.Total,
+		"facets":       res.Facets,
+		"fuzzy":        res.Fuzzy,
+		"did_you_mean": suggestion,
+	})
+}
+
+// queryList reads a filter given either as ?k=a,b or ?k=a&k=b, lowercased.
+func queryList(params url.Values, key string) []string {
+	var out []string
+	for _, raw := range params[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
 }
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"log"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/search"
+)
+
+// indexVendor pushes the listing's current row to the search index. Call it
+// after every committed change to a listing or its deals. Failures are only
+// logged: the listing itself was saved, and the index catches up on the next
+// write.
+func indexVendor(ctx context.Context, vendorID int) {
+	var doc search.Document
+	err := config.DB.QueryRowContext(ctx, `
+		SELECT v.id, v.vendor_id, v.title, COALESCE(v.slug, ''), COALESCE(v.description, ''), COALESCE(v.category, ''),
+		       COALESCE(v.price_range, ''), COALESCE(v.location, ''), COALESCE(v.city, ''), v.latitude, v.longitude,
+		       v.photos, COALESCE(v.rating, 0)::float8, COALESCE(v.featured, false), COALESCE(v.language, 'en'),
+		       v.created_at, v.updated_at, v.price_min,
+		       EXISTS (
+		           SELECT 1 FROM vendor_deals d
+		           WHERE d.vendor_id = v.id AND NOW() BETWEEN d.start_date AND d.end_date
+		       )
+		FROM vendors v WHERE v.id = $1
+	`, vendorID).Scan(
+		&doc.ID, &doc.VendorID, &doc.Title, &doc.Slug, &doc.Description, &doc.Category,
+		&doc.PriceRange, &doc.Location, &doc.City, &doc.Latitude, &doc.Longitude,
+		&doc.Photos, &doc.Rating, &doc.Featured, &doc.Language,
+		&doc.CreatedAt, &doc.UpdatedAt, &doc.PriceMin, &doc.HasDeal,
+	)
+	if err != nil {
+		log.Printf("Error loading vendor %d for the search index: %v", vendorID, err)
+		return
+	}
+	if err := search.Vendors.Index(ctx, doc); err != nil {
+		log.Printf("Error indexing vendor %d: %v", vendorID, err)
+	}
+}
+
+// unindexVendor removes a deleted listing from the search index.
+func unindexVendor(ctx context.Context, vendorID int) {
+	if err := search.Vendors.Delete(ctx, vendorID); err != nil {
+		log.Printf("Error removing vendor %d from the search index: %v", vendorID, err)
+	}
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	// typeahead answers late are useless; give up and return nothing instead
+	suggestTimeout       = 250 * time.Millisecond
+	suggestVendorLimit   = 8
+	suggestFacetLimit    = 5
+	suggestMaxQueryRunes = 64
+)
+
+var suggestWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// GET /search/suggest?q=
+// Matches listing titles by word prefix ("photo" finds "Lens Photography")
+// or trigram similarity for typos, plus categories and cities. Vendors are
+// ranked by popularity (saves + bookings), categories and cities by the
+// number of listings.
+func SuggestSearch(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if r := []rune(q); len(r) > suggestMaxQueryRunes {
+		q = string(r[:suggestMaxQueryRunes])
+	}
+
+	out := models.SearchSuggestions{
+		Vendors:    []models.VendorSuggestion{},
+		Categories: []models.FacetCount{},
+		Cities:     []models.FacetCount{},
+	}
+
+	words := suggestWord.FindAllString(strings.ToLower(q), 8)
+	if len(words) == 0 {
+		utils.RespondWithJSON(c, http.StatusOK, out)
+		return
+	}
+
+	prefixQuery := make([]string, len(words))
+	for i, w := range words {
+		prefixQuery[i] = w + ":*"
+	}
+	last := words[len(words)-1]
+
+	var categories []string
+	for _, cat := range models.VendorCategories {
+		if strings.HasPrefix(cat, last) {
+			categories = append(categories, cat)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), suggestTimeout)
+	defer cancel()
+
+	rows, err := config.DB.QueryContext(ctx, `
+		(
+			WITH hits AS (
+				SELECT v.id, v.title, COALESCE(v.slug, '') AS slug, COALESCE(v.category, '') AS category,
+				       COALESCE(v.city, '') AS city,
+				       to_tsvector('simple', v.title) @@ to_tsquery('simple', $1) AS is_prefix,
+				       similarity(v.title, $2) AS sim
+				FROM vendors v
+				WHERE to_tsvector('simple', v.title) @@ to_tsquery('simple', $1) OR v.title % $2
+				ORDER BY is_prefix DESC, sim DESC
+				LIMIT 50
+			)
+			SELECT 'vendor', h.id, h.title, h.slug, h.category, h.city,
+			       (SELECT COUNT(*) FROM saved_items s WHERE s.vendor_id = h.id) +
+			       (SELECT COUNT(*) FROM bookings b WHERE b.vendor_id = h.id) AS popularity
+			FROM hits h
+			ORDER BY h.is_prefix DESC, popularity DESC, h.sim DESC
+			LIMIT $3
+		)
+		UNION ALL
+		(
+			SELECT 'category', 0, category, '', category, '', COUNT(*)
+			FROM vendors
+			WHERE category = ANY($4)
+			GROUP BY category
+			ORDER BY COUNT(*) DESC
+			LIMIT $5
+		)
+		UNION ALL
+		(
+			SELECT 'city', 0, city, '', '', city, COUNT(*)
+			FROM vendors
+			WHERE city ILIKE $6 OR city % $2
+			GROUP BY city
+			ORDER BY COUNT(*) DESC
+			LIMIT $5
+		)
+	`, strings.Join(prefixQuery, " & "), q, suggestVendorLimit, pq.StringArray(categories), suggestFacetLimit,
+		escapeLike(last)+"%")
+	if err != nil {
+		log.Printf("SuggestSearch query error: %v", err)
+		utils.RespondWithJSON(c, http.StatusOK, out)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var s models.VendorSuggestion
+		if err := rows.Scan(&kind, &s.ID, &s.Title, &s.Slug, &s.Category, &s.City, &s.Popularity); err != nil {
+			log.Printf("SuggestSearch scan error: %v", err)
+			break
+		}
+		switch kind {
+		case "vendor":
+			out.Vendors = append(out.Vendors, s)
+		case "category":
+			out.Categories = append(out.Categories, models.FacetCount{Value: s.Category, Count: s.Popularity})
+		case "city":
+			out.Cities = append(out.Cities, models.FacetCount{Value: s.City, Count: s.Popularity})
+		}
+	}
+
+	utils.RespondWithJSON(c, http.StatusOK, out)
+}
+
+// escapeLike escapes the LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
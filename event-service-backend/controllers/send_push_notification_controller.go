@@ -7,9 +7,8 @@ import (
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/utils"
 	"github.com/gin-gonic/gin"
-
-	expo "github.com/oliveroneill/exponent-server-sdk-golang/sdk"
 )
 
 type ExpoMessage struct {
@@ -84,38 +83,18 @@ func SendPushNotification(c *gin.Context) {
 		return
 	}
 
-	// 🔍 Get push token from DB
-	var token string
-	err := config.DB.QueryRow(`SELECT token FROM push_tokens WHERE user_id=$1`, req.UserID).Scan(&token)
-	if err != nil {
+	resp, err := utils.SendPush(req.UserID, req.Title, req.Body, req.Sound, req.Data)
+	switch {
+	case err == utils.ErrNoPushToken:
 		c.JSON(404, gin.H{"error": "No push token for this user"})
 		return
-	}
-
-	pushToken, err := expo.NewExponentPushToken(token)
-	if err != nil {
+	case err == utils.ErrInvalidPushToken:
 		c.JSON(400, gin.H{"error": "Invalid Expo push token"})
 		return
-	}
-
-	client := expo.NewPushClient(nil)
-
-	msg := &expo.PushMessage{
-		To:       []expo.ExponentPushToken{pushToken},
-		Title:    req.Title,
-		Body:     req.Body,
-		Sound:    req.Sound,
-		Data:     req.Data,
-		Priority: expo.DefaultPriority,
-	}
-
-	resp, err := client.Publish(msg)
-	if err != nil {
+	case err != nil && resp.Status == "":
 		c.JSON(500, gin.H{"error": "Failed to publish notification"})
 		return
-	}
-
-	if err := resp.ValidateResponse(); err != nil {
+	case err != nil:
 		c.JSON(500, gin.H{"error": "Expo rejected message"})
 		return
 	}
@@ -52,12 +52,23 @@ func CreateUser(c *gin.Context) {
 	utils.RespondWithJSON(c, http.StatusCreated, user)
 }
 
+// GET /users?limit=&cursor=
 func GetAllUsers(c *gin.Context) {
+	page, err := utils.ParsePage(c, map[string]utils.SortKey{
+		utils.SortNewest: {Expr: "created_at", Desc: true, Cast: "timestamptz"},
+	}, utils.SortNewest, "id")
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var args utils.SQLArgs
 	rows, err := config.DB.Query(`
-		SELECT id, clerk_id, full_name, email, role, created_at, updated_at 
+		SELECT id, clerk_id, full_name, email, role, created_at, updated_at, `+page.SortValue()+`
 		FROM users
-		ORDER BY created_at DESC
-	`)
+		WHERE `+page.Where(args.Add)+`
+		ORDER BY `+page.OrderBy()+`
+		LIMIT `+args.Add(page.FetchLimit()), args...)
 	if err != nil {
 		log.Printf("Error fetching users: %v", err)
 		utils.RespondWithError(c, http.StatusInternalServerError, "Failed to fetch users")
@@ -65,10 +76,11 @@ func GetAllUsers(c *gin.Context) {
 	}
 	defer rows.Close()
 
-	var users []models.User
+	users := []models.User{}
 
 	for rows.Next() {
 		var user models.User
+		var sortValue string
 		err := rows.Scan(
 			&user.ID,
 			&user.ClerkID,
@@ -77,13 +89,16 @@ func GetAllUsers(c *gin.Context) {
 			&user.Role,
 			&user.CreatedAt,
 			&user.UpdatedAt,
+			&sortValue,
 		)
 		if err != nil {
 			log.Printf("Error scanning user: %v", err)
 			continue
 		}
+		page.Track(sortValue, user.ID)
 		users = append(users, user)
 	}
 
-	utils.RespondWithJSON(c, http.StatusOK, users)
+	n, next := page.Done()
+	utils.RespondWithPage(c, users[:n], &page, next)
 }
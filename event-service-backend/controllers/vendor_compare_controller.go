@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	minCompareVendors = 2
+	maxCompareVendors = 4
+)
+
+// GET /vendors/compare?ids=1,2,3&date=2025-12-31&lat=&lng=
+// Compares 2 to 4 vendors, in the order given. date (YYYY-MM-DD) fills in
+// availability and lat/lng fill in distance_km. best names the vendor id
+// with the lowest starting price, the highest rating and the shortest
+// distance, when there is one to pick.
+func CompareVendors(c *gin.Context) {
+	var ids []int64
+	seen := map[int64]bool{}
+	for _, raw := range queryList(c.Request.URL.Query(), "ids") {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			utils.RespondWithError(c, http.StatusBadRequest, "ids must be vendor ids like 1,2,3")
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < minCompareVendors || len(ids) > maxCompareVendors {
+		utils.RespondWithError(c, http.StatusBadRequest, "Compare between 2 and 4 different vendors")
+		return
+	}
+
+	var date *time.Time
+	if raw := strings.TrimSpace(c.Query("date")); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			utils.RespondWithError(c, http.StatusBadRequest, "date must be a date like 2025-12-31")
+			return
+		}
+		date = &d
+	}
+	var lat, lng *float64
+	if c.Query("lat") != "" || c.Query("lng") != "" {
+		la, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+		ln, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+		if errLat != nil || errLng != nil {
+			utils.RespondWithError(c, http.StatusBadRequest, "lat and lng must be numbers given together")
+			return
+		}
+		lat, lng = &la, &ln
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, vendor_id, title, COALESCE(slug, ''), description, category, price_range, location,
+		       COALESCE(city, ''), latitude, longitude, photos, COALESCE(rating, 0)::float8, COALESCE(featured, false),
+		       created_at, updated_at, price_min::float8, price_max::float8,
+		       CASE WHEN $2::date IS NULL THEN NULL ELSE vendor_is_available(id, $2::date, $2::date) END,
+		       distance_km($3, $4, latitude, longitude)
+		FROM vendors
+		WHERE id = ANY($1)
+	`, pq.Int64Array(ids), date, lat, lng)
+	if err != nil {
+		log.Printf("CompareVendors query error: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not compare vendors")
+		return
+	}
+	defer rows.Close()
+
+	byID := map[int]*models.VendorComparison{}
+	for rows.Next() {
+		cmp := &models.VendorComparison{ActiveDeals: []models.VendorDeal{}}
+		v := &cmp.Vendor
+		if err := rows.Scan(&v.ID, &v.VendorID, &v.Title, &v.Slug, &v.Description, &v.Category, &v.PriceRange,
+			&v.Location, &v.City, &v.Latitude, &v.Longitude, &v.Photos, &v.Rating, &v.Featured,
+			&v.CreatedAt, &v.UpdatedAt, &cmp.PriceMin, &cmp.PriceMax, &cmp.Available, &cmp.DistanceKm); err != nil {
+			log.Printf("CompareVendors scan error: %v", err)
+			utils.RespondWithError(c, http.StatusInternalServerError, "Could not compare vendors")
+			return
+		}
+		cmp.Rating = v.Rating
+		byID[v.ID] = cmp
+	}
+	if len(byID) != len(ids) {
+		utils.RespondWithError(c, http.StatusNotFound, "One or more vendors not found")
+		return
+	}
+
+	if err := loadActiveDeals(ids, byID); err != nil {
+		log.Printf("CompareVendors deals error: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not compare vendors")
+		return
+	}
+
+	vendors := make([]models.VendorComparison, 0, len(ids))
+	for _, id := range ids {
+		vendors = append(vendors, *byID[int(id)])
+	}
+
+	utils.RespondWithJSON(c, http.StatusOK, gin.H{
+		"vendors": vendors,
+		"best":    bestCompared(vendors),
+	})
+}
+
+// loadActiveDeals attaches the deals running today, biggest discount first.
+func loadActiveDeals(ids []int64, byID map[int]*models.VendorComparison) error {
+	rows, err := config.DB.Query(`
+		SELECT id, vendor_id, title, description, discount_percent, original_price, deal_price,
+		       start_date, end_date, photos, created_at
+		FROM vendor_deals
+		WHERE vendor_id = ANY($1) AND start_date <= NOW() AND end_date >= NOW()
+		ORDER BY discount_percent DESC, id
+	`, pq.Int64Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deal models.VendorDeal
+		var photos []string
+		if err := rows.Scan(&deal.ID, &deal.VendorID, &deal.Title, &deal.Description, &deal.DiscountPercent,
+			&deal.OriginalPrice, &deal.DealPrice, &deal.StartDate, &deal.EndDate, pq.Array(&photos),
+			&deal.CreatedAt); err != nil {
+			return err
+		}
+		deal.Photos = photos
+		if cmp := byID[deal.VendorID]; cmp != nil {
+			cmp.ActiveDeals = append(cmp.ActiveDeals, deal)
+		}
+	}
+	return rows.Err()
+}
+
+// bestCompared picks the winning vendor id per attribute. Ties and
+// attributes no vendor has are left out.
+func bestCompared(vendors []models.VendorComparison) gin.H {
+	best := gin.H{}
+	pick := func(key string, value func(models.VendorComparison) *float64, lowest bool) {
+		var ranked []models.VendorComparison
+		for _, v := range vendors {
+			if value(v) != nil {
+				ranked = append(ranked, v)
+			}
+		}
+		if len(ranked) == 0 {
+			return
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			a, b := *value(ranked[i]), *value(ranked[j])
+			if lowest {
+				return a < b
+			}
+			return a > b
+		})
+		if len(ranked) > 1 && *value(ranked[0]) == *value(ranked[1]) {
+			return
+		}
+		best[key] = ranked[0].Vendor.ID
+	}
+
+	pick("price", func(v models.VendorComparison) *float64 { return v.PriceMin }, true)
+	pick("rating", func(v models.VendorComparison) *float64 {
+		if v.Rating == 0 {
+			return nil
+		}
+		return &v.Rating
+	}, false)
+	pick("distance", func(v models.VendorComparison) *float64 { return v.DistanceKm }, true)
+	return best
+}
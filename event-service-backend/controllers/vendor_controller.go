@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dharmaseervi/event-service-backend/config"
@@ -24,13 +27,28 @@ func CreateVendor(c *gin.Context) {
 	}
 	log.Printf("Vendor data: %+v", vendor)
 
+	lang, ok := normalizeLanguage(vendor.Language)
+	if !ok {
+		utils.RespondWithError(c, http.StatusBadRequest, "language must be a code like en or hi")
+		return
+	}
+	vendor.Language = lang
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not create vendor")
+		return
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO vendors 
-			(vendor_id, title, description, category, price_range, location, photos, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			(vendor_id, title, description, category, price_range, location, latitude, longitude, language, photos, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id, created_at, updated_at
 	`
-	err := config.DB.QueryRow(
+	err = tx.QueryRow(
 		query,
 		vendor.VendorID,
 		vendor.Title,
@@ -38,6 +56,9 @@ func CreateVendor(c *gin.Context) {
 		vendor.Category,
 		vendor.PriceRange,
 		vendor.Location,
+		vendor.Latitude,
+		vendor.Longitude,
+		vendor.Language,
 		pq.StringArray(vendor.Photos), // ✅ convert []string properly
 		time.Now(),
 		time.Now(),
@@ -49,34 +70,49 @@ func CreateVendor(c *gin.Context) {
 		return
 	}
 
+	vendor.Slug, err = syncVendorSlug(tx, vendor.ID, vendor.Title, vendor.Location)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("Error creating vendor slug: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not create vendor")
+		return
+	}
+	indexVendor(c.Request.Context(), vendor.ID)
+
 	c.JSON(http.StatusCreated, vendor)
 }
 
+// GET /vendors?category=&limit=&sort=newest|rating|price|distance&cursor=
 func GetAllVendors(c *gin.Context) {
 	// Get category filter from query params
 	category := c.Query("category")
 
-	var query string
-	var rows *sql.Rows
-	var err error
+	var args utils.SQLArgs
+	sorts, err := vendorSorts(c, "", &args)
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := utils.ParsePage(c, sorts, utils.SortNewest, "id")
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
+	query := `
+		SELECT id, vendor_id, title, COALESCE(slug, ''), description, category, price_range, location,
+		       latitude, longitude, photos, COALESCE(rating, 0)::float8, COALESCE(featured, false),
+		       created_at, updated_at, ` + page.SortValue() + `
+		FROM vendors
+		WHERE ` + page.Where(args.Add)
 	if category != "" {
-		query = `
-			SELECT id, vendor_id, title, description, category, price_range, location, photos, created_at, updated_at
-			FROM vendors 
-			WHERE category = $1
-			ORDER BY created_at DESC
-		`
-		rows, err = config.DB.Query(query, category)
-	} else {
-		query = `
-			SELECT id, vendor_id, title, description, category, price_range, location, photos, created_at, updated_at
-			FROM vendors
-			ORDER BY created_at DESC
-		`
-		rows, err = config.DB.Query(query)
+		query += ` AND category = ` + args.Add(category)
 	}
+	query += ` ORDER BY ` + page.OrderBy() + ` LIMIT ` + args.Add(page.FetchLimit())
 
+	rows, err := config.DB.Query(query, args...)
 	if err != nil {
 		log.Printf("Error querying vendors: %v", err)
 		utils.RespondWithError(c, http.StatusInternalServerError, "Could not retrieve vendors")
@@ -87,47 +123,62 @@ func GetAllVendors(c *gin.Context) {
 	vendors := []models.VendorListing{}
 	for rows.Next() {
 		var vendor models.VendorListing
+		var sortValue string
 		if err := rows.Scan(
 			&vendor.ID,
 			&vendor.VendorID,
 			&vendor.Title,
+			&vendor.Slug,
 			&vendor.Description,
 			&vendor.Category,
 			&vendor.PriceRange,
 			&vendor.Location,
+			&vendor.Latitude,
+			&vendor.Longitude,
 			(*pq.StringArray)(&vendor.Photos),
+			&vendor.Rating,
+			&vendor.Featured,
 			&vendor.CreatedAt,
 			&vendor.UpdatedAt,
+			&sortValue,
 		); err != nil {
 			log.Printf("Error scanning vendor: %v", err)
 			utils.RespondWithError(c, http.StatusInternalServerError, "Could not retrieve vendors")
 			return
 		}
+		page.Track(sortValue, vendor.ID)
 		vendors = append(vendors, vendor)
 	}
 
-	utils.RespondWithJSON(c, http.StatusOK, vendors)
+	n, next := page.Done()
+	utils.RespondWithPage(c, vendors[:n], &page, next)
 }
 
+// GET /vendors/:id
+// :id may be the numeric id or the listing's slug. A slug the listing had
+// before a rename answers with a 301 to the current one. The listing comes
+// with question_count and its latest answered questions.
 func GetVendorByID(c *gin.Context) {
-	id := c.Param("id") // Get ID from URL
-	category := c.Param("category")
+	id := c.Param("id") // Get ID or slug from URL
 
 	log.Printf("Fetching vendor with ID: %s", id)
 	var vendor models.VendorListing
 
 	query := `
-		SELECT id, vendor_id, title, description, category, price_range, location, photos, created_at, updated_at
-		FROM vendors WHERE id = $1
+		SELECT id, vendor_id, title, COALESCE(slug, ''), description, category, price_range, location, photos, created_at, updated_at
+		FROM vendors
 	`
-	if category != "" {
-		query += ` AND category = $2`
+	if _, err := strconv.Atoi(id); err == nil {
+		query += ` WHERE id = $1`
+	} else {
+		query += ` WHERE slug = $1`
 	}
 
 	err := config.DB.QueryRow(query, id).Scan(
 		&vendor.ID,
 		&vendor.VendorID,
 		&vendor.Title,
+		&vendor.Slug,
 		&vendor.Description,
 		&vendor.Category,
 		&vendor.PriceRange,
@@ -137,11 +188,102 @@ func GetVendorByID(c *gin.Context) {
 		&vendor.UpdatedAt,
 	)
 
+	if err == sql.ErrNoRows {
+		var currentSlug string
+		if err := config.DB.QueryRow(`
+			SELECT v.slug FROM vendor_slug_history h
+			JOIN vendors v ON v.id = h.vendor_id
+			WHERE h.slug = $1 AND v.slug IS NOT NULL
+		`, id).Scan(&currentSlug); err == nil {
+			target := "/vendors/" + currentSlug
+			if c.Request.URL.RawQuery != "" {
+				target += "?" + c.Request.URL.RawQuery
+			}
+			c.Redirect(http.StatusMovedPermanently, target)
+			return
+		}
+	}
+
 	if err != nil {
 		log.Printf("Error fetching vendor by ID: %v", err)
 		utils.RespondWithError(c, http.StatusNotFound, "Vendor not found")
 		return
 	}
+	recordSearchClick(c, vendor.ID)
+
+	detail := models.VendorDetail{VendorListing: vendor, LatestQuestions: []models.VendorQuestion{}}
+	if detail.QuestionCount, detail.LatestQuestions, err = vendorQuestionPreview(vendor.ID); err != nil {
+		log.Printf("Error fetching vendor questions: %v", err)
+		detail.LatestQuestions = []models.VendorQuestion{}
+	}
+
+	utils.RespondWithJSON(c, http.StatusOK, detail)
+}
+
+// PUT /vendors/:id
+// Only the vendor who owns the listing may edit it. Changing the title or
+// city gives the listing a new slug; the old one keeps redirecting.
+func UpdateVendor(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, "Invalid vendor ID")
+		return
+	}
+
+	var input models.VendorListing
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Title) == "" {
+		utils.RespondWithError(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+	lang, ok := normalizeLanguage(input.Language)
+	if !ok {
+		utils.RespondWithError(c, http.StatusBadRequest, "language must be a code like en or hi")
+		return
+	}
+	input.Language = lang
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not update vendor")
+		return
+	}
+	defer tx.Rollback()
+
+	vendor := input
+	err = tx.QueryRow(`
+		UPDATE vendors
+		SET title=$3, description=$4, category=$5, price_range=$6, location=$7, latitude=$8, longitude=$9,
+		    language=$10, photos=$11, updated_at=$12
+		WHERE id=$1 AND vendor_id=$2
+		RETURNING id, vendor_id, created_at, updated_at
+	`, id, userID, input.Title, input.Description, input.Category, input.PriceRange, input.Location,
+		input.Latitude, input.Longitude, input.Language, pq.StringArray(input.Photos), time.Now(),
+	).Scan(&vendor.ID, &vendor.VendorID, &vendor.CreatedAt, &vendor.UpdatedAt)
+	if err == sql.ErrNoRows {
+		utils.RespondWithError(c, http.StatusNotFound, "Vendor not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating vendor: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not update vendor")
+		return
+	}
+
+	vendor.Slug, err = syncVendorSlug(tx, vendor.ID, vendor.Title, vendor.Location)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Printf("Error updating vendor slug: %v", err)
+		utils.RespondWithError(c, http.StatusInternalServerError, "Could not update vendor")
+		return
+	}
+	indexVendor(c.Request.Context(), vendor.ID)
 
 	utils.RespondWithJSON(c, http.StatusOK, vendor)
 }
@@ -164,6 +306,9 @@ func DeleteVendor(c *gin.Context) {
 		utils.RespondWithError(c, http.StatusNotFound, "Vendor not found")
 		return
 	}
+	if vendorID, err := strconv.Atoi(id); err == nil {
+		unindexVendor(c.Request.Context(), vendorID)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Vendor deleted successfully"})
 }
@@ -256,3 +401,15 @@ func GetRecommendedVendors(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"vendors": recommendations})
 }
+
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// normalizeLanguage lowercases an ISO 639 language code, defaulting to "en".
+// The database maps codes to text search configs (vendor_search_config).
+func normalizeLanguage(lang string) (string, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return "en", true
+	}
+	return lang, languageCode.MatchString(lang)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dharmaseervi/event-service-backend/config"
 	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
@@ -37,33 +38,53 @@ func CreateVendorDeals(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// has_deal is part of the indexed listing
+	indexVendor(c.Request.Context(), deal.VendorID)
 	c.JSON(http.StatusCreated, deal)
 }
 
+// GET /vendor-deals?limit=&sort=newest|price&cursor=
 func GetAllVendorsDeals(c *gin.Context) {
+	page, err := utils.ParsePage(c, map[string]utils.SortKey{
+		utils.SortNewest: {Expr: "created_at", Desc: true, Cast: "timestamptz"},
+		utils.SortPrice:  {Expr: "COALESCE(deal_price, 0)::float8", Cast: "float8"},
+	}, utils.SortNewest, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
+	var args utils.SQLArgs
 	rows, err := config.DB.Query(`
-			SELECT id, vendor_id, title, description, discount_percent, original_price, deal_price, start_date, end_date, photos, created_at
-			FROM vendor_deals 
-		`)
+			SELECT id, vendor_id, title, description, discount_percent, original_price, deal_price, start_date, end_date, photos, created_at,
+			       `+page.SortValue()+`
+			FROM vendor_deals
+			WHERE `+page.Where(args.Add)+`
+			ORDER BY `+page.OrderBy()+`
+			LIMIT `+args.Add(page.FetchLimit()), args...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	defer rows.Close()
 
-	var deals []models.VendorDeal
+	deals := []models.VendorDeal{}
 	for rows.Next() {
 		var deal models.VendorDeal
 		var photos []string
+		var sortValue string
 		err := rows.Scan(&deal.ID, &deal.VendorID, &deal.Title, &deal.Description, &deal.DiscountPercent,
-			&deal.OriginalPrice, &deal.DealPrice, &deal.StartDate, &deal.EndDate, pq.Array(&photos), &deal.CreatedAt)
+			&deal.OriginalPrice, &deal.DealPrice, &deal.StartDate, &deal.EndDate, pq.Array(&photos), &deal.CreatedAt,
+			&sortValue)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 		deal.Photos = photos
+		page.Track(sortValue, deal.ID)
 		deals = append(deals, deal)
 	}
-	c.JSON(http.StatusOK, deals)
+
+	n, next := page.Done()
+	utils.RespondWithPage(c, deals[:n], &page, next)
 }
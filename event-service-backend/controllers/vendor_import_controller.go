@@ -0,0 +1,478 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	maxVendorImportBytes = 10 << 20 // 10 MB
+	// progress is flushed to vendor_import_jobs every N rows
+	vendorImportProgressEvery = 50
+)
+
+// vendorImportRow is one listing as it appears in the uploaded file. CSV files
+// use the same names as header columns, with photos separated by "|".
+type vendorImportRow struct {
+	VendorID    int      `json:"vendor_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	PriceRange  string   `json:"price_range"`
+	Location    string   `json:"location"`
+	Photos      []string `json:"photos"`
+	ExternalRef string   `json:"external_ref"`
+}
+
+type parsedImportRow struct {
+	Row  int
+	Data vendorImportRow
+	Err  error
+}
+
+// POST /admin/vendor-imports?format=csv|jsonl&dry_run=true&upsert=true
+// body: the file itself, or multipart form field "file"
+func CreateVendorImport(c *gin.Context) {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "csv")))
+	if format != "csv" && format != "jsonl" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or jsonl"})
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+	upsert := c.Query("upsert") == "true"
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxVendorImportBytes)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file"})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	var rows []parsedImportRow
+	var err error
+	if format == "csv" {
+		rows, err = parseVendorImportCSV(body)
+	} else {
+		rows, err = parseVendorImportJSONL(body)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file has no rows"})
+		return
+	}
+
+	job := models.VendorImportJob{
+		CreatedBy: adminID,
+		Format:    format,
+		DryRun:    dryRun,
+		Upsert:    upsert,
+		Status:    "pending",
+		TotalRows: len(rows),
+		Errors:    []models.VendorImportRowError{},
+	}
+	err = config.DB.QueryRow(`
+		INSERT INTO vendor_import_jobs (created_by, format, dry_run, upsert, status, total_rows)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, job.CreatedBy, job.Format, job.DryRun, job.Upsert, job.Status, job.TotalRows).Scan(&job.ID, &job.CreatedAt)
+	if err != nil {
+		log.Printf("CreateVendorImport insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create import job"})
+		return
+	}
+
+	go runVendorImport(job, rows)
+
+	c.JSON(http.StatusAccepted, gin.H{"job": job})
+}
+
+// GET /admin/vendor-imports/:id
+func GetVendorImport(c *gin.Context) {
+	job, ok := loadVendorImportJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+// GET /admin/vendor-imports/:id/errors
+// Downloads the per-row error report as CSV.
+func GetVendorImportErrors(c *gin.Context) {
+	job, ok := loadVendorImportJob(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="vendor-import-%d-errors.csv"`, job.ID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"row", "external_ref", "error"})
+	for _, e := range job.Errors {
+		w.Write([]string{strconv.Itoa(e.Row), e.ExternalRef, e.Error})
+	}
+	w.Flush()
+}
+
+func loadVendorImportJob(c *gin.Context) (models.VendorImportJob, bool) {
+	var job models.VendorImportJob
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return job, false
+	}
+
+	var createdBy sql.NullInt64
+	var errorsJSON []byte
+	err = config.DB.QueryRow(`
+		SELECT id, created_by, format, dry_run, upsert, status, total_rows, processed_rows,
+		       created_rows, updated_rows, failed_rows, errors, created_at, finished_at
+		FROM vendor_import_jobs WHERE id = $1
+	`, id).Scan(
+		&job.ID, &createdBy, &job.Format, &job.DryRun, &job.Upsert, &job.Status, &job.TotalRows,
+		&job.ProcessedRows, &job.CreatedRows, &job.UpdatedRows, &job.FailedRows, &errorsJSON,
+		&job.CreatedAt, &job.FinishedAt,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+		return job, false
+	}
+	if err != nil {
+		log.Printf("loadVendorImportJob query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return job, false
+	}
+	job.CreatedBy = int(createdBy.Int64)
+	if err := json.Unmarshal(errorsJSON, &job.Errors); err != nil {
+		log.Printf("loadVendorImportJob errors decode: %v", err)
+	}
+	return job, true
+}
+
+func parseVendorImportCSV(r io.Reader) ([]parsedImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	cols, err := readCSVHeader(cr)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := cols["title"]; !ok {
+		return nil, errors.New("CSV header must include a title column")
+	}
+	get := func(rec []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var rows []parsedImportRow
+	for n := 1; ; n++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				// read errors (including the size limit) are not row problems
+				return nil, errors.New("could not read file (max 10 MB)")
+			}
+			rows = append(rows, parsedImportRow{Row: n, Err: err})
+			continue
+		}
+
+		row := vendorImportRow{
+			Title:       get(rec, "title"),
+			Description: get(rec, "description"),
+			Category:    get(rec, "category"),
+			PriceRange:  get(rec, "price_range"),
+			Location:    get(rec, "location"),
+			ExternalRef: get(rec, "external_ref"),
+		}
+		if v := get(rec, "vendor_id"); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				rows = append(rows, parsedImportRow{Row: n, Data: row, Err: errors.New("vendor_id must be a number")})
+				continue
+			}
+			row.VendorID = id
+		}
+		if p := get(rec, "photos"); p != "" {
+			for _, photo := range strings.Split(p, "|") {
+				if photo = strings.TrimSpace(photo); photo != "" {
+					row.Photos = append(row.Photos, photo)
+				}
+			}
+		}
+		rows = append(rows, parsedImportRow{Row: n, Data: row})
+	}
+	return rows, nil
+}
+
+func parseVendorImportJSONL(r io.Reader) ([]parsedImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.New("could not read file (max 10 MB)")
+	}
+
+	var rows []parsedImportRow
+	n := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		n++
+		var row vendorImportRow
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			rows = append(rows, parsedImportRow{Row: n, Err: fmt.Errorf("invalid JSON: %v", err)})
+			continue
+		}
+		row.Title = strings.TrimSpace(row.Title)
+		row.Category = strings.TrimSpace(row.Category)
+		row.ExternalRef = strings.TrimSpace(row.ExternalRef)
+		rows = append(rows, parsedImportRow{Row: n, Data: row})
+	}
+	return rows, nil
+}
+
+func validateVendorImportRow(row vendorImportRow) error {
+	if row.VendorID <= 0 {
+		return errors.New("vendor_id is required")
+	}
+	if row.Title == "" {
+		return errors.New("title is required")
+	}
+	if !slices.Contains(models.VendorCategories, row.Category) {
+		return fmt.Errorf("category must be one of %s", strings.Join(models.VendorCategories, ", "))
+	}
+	return nil
+}
+
+// runVendorImport validates and writes every row, recording progress and the
+// error report on the job as it goes. Rows are independent: one bad row does
+// not stop the rest of the file. A panic fails the job; if the process dies
+// instead, jobs.FailStaleVendorImports fails it once its progress goes stale.
+func runVendorImport(job models.VendorImportJob, rows []parsedImportRow) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Vendor import %d panicked: %v", job.ID, r)
+			now := time.Now()
+			job.Status = "failed"
+			job.FinishedAt = &now
+			saveVendorImportProgress(&job)
+		}
+	}()
+
+	job.Status = "running"
+	saveVendorImportProgress(&job)
+
+	knownVendors := map[int]bool{}
+	seenRefs := map[string]int{}
+
+	for _, r := range rows {
+		err := r.Err
+		if err == nil {
+			err = validateVendorImportRow(r.Data)
+		}
+		if err == nil {
+			err = checkImportVendor(knownVendors, r.Data.VendorID)
+		}
+		if err == nil && r.Data.ExternalRef != "" {
+			key := fmt.Sprintf("%d/%s", r.Data.VendorID, r.Data.ExternalRef)
+			if first, dup := seenRefs[key]; dup {
+				err = fmt.Errorf("external_ref duplicates row %d", first)
+			} else {
+				seenRefs[key] = r.Row
+			}
+		}
+
+		var created bool
+		if err == nil {
+			if job.DryRun {
+				created, err = checkVendorImportRow(r.Data, job.Upsert)
+			} else {
+				created, err = writeVendorImportRow(r.Data, job.Upsert)
+			}
+		}
+
+		job.ProcessedRows++
+		switch {
+		case err != nil:
+			job.FailedRows++
+			job.Errors = append(job.Errors, models.VendorImportRowError{
+				Row:         r.Row,
+				ExternalRef: r.Data.ExternalRef,
+				Error:       err.Error(),
+			})
+		case created:
+			job.CreatedRows++
+		default:
+			job.UpdatedRows++
+		}
+
+		if job.ProcessedRows%vendorImportProgressEvery == 0 {
+			saveVendorImportProgress(&job)
+		}
+	}
+
+	now := time.Now()
+	job.Status = "completed"
+	job.FinishedAt = &now
+	saveVendorImportProgress(&job)
+	log.Printf("Vendor import %d finished: %d created, %d updated, %d failed (dry_run=%v)",
+		job.ID, job.CreatedRows, job.UpdatedRows, job.FailedRows, job.DryRun)
+}
+
+// checkImportVendor fails rows whose vendor_id is not a user. Lookups are
+// cached in known; failed ones are not, so the next row asks again.
+func checkImportVendor(known map[int]bool, vendorID int) error {
+	exists, checked := known[vendorID]
+	if !checked {
+		err := config.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM users WHERE id=$1)`, vendorID).Scan(&exists)
+		if err != nil {
+			log.Printf("checkImportVendor query error: %v", err)
+			return errors.New("could not check vendor_id")
+		}
+		known[vendorID] = exists
+	}
+	if !exists {
+		return errors.New("vendor_id does not match a user")
+	}
+	return nil
+}
+
+// checkVendorImportRow reports what writeVendorImportRow would do without
+// writing anything.
+func checkVendorImportRow(row vendorImportRow, upsert bool) (bool, error) {
+	if row.ExternalRef == "" {
+		return true, nil
+	}
+	var exists bool
+	err := config.DB.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM vendors WHERE vendor_id=$1 AND external_ref=$2)`,
+		row.VendorID, row.ExternalRef,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("checkVendorImportRow query error: %v", err)
+		return false, errors.New("could not check listing")
+	}
+	if exists && !upsert {
+		return false, errors.New("external_ref already exists (use upsert=true to update)")
+	}
+	return !exists, nil
+}
+
+// writeVendorImportRow inserts the listing, or updates the existing one with
+// the same external_ref when upsert is set. It reports whether a new row was
+// created.
+func writeVendorImportRow(row vendorImportRow, upsert bool) (bool, error) {
+	var externalRef sql.NullString
+	if row.ExternalRef != "" {
+		externalRef = sql.NullString{String: row.ExternalRef, Valid: true}
+	}
+
+	query := `
+		INSERT INTO vendors
+			(vendor_id, title, description, category, price_range, location, photos, external_ref, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`
+	if upsert {
+		query += `
+		ON CONFLICT (vendor_id, external_ref) WHERE external_ref IS NOT NULL DO UPDATE SET
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			category = EXCLUDED.category,
+			price_range = EXCLUDED.price_range,
+			location = EXCLUDED.location,
+			photos = EXCLUDED.photos,
+			updated_at = EXCLUDED.updated_at
+		`
+	}
+	// xmax is 0 only for freshly inserted tuples
+	query += ` RETURNING id, (xmax = 0)`
+
+	var id int
+	var created bool
+	err := config.DB.QueryRow(
+		query,
+		row.VendorID,
+		row.Title,
+		row.Description,
+		row.Category,
+		row.PriceRange,
+		row.Location,
+		pq.StringArray(row.Photos),
+		externalRef,
+		time.Now(),
+	).Scan(&id, &created)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return false, errors.New("external_ref already exists (use upsert=true to update)")
+		}
+		log.Printf("writeVendorImportRow error: %v", err)
+		return false, errors.New("could not save listing")
+	}
+
+	if _, err := syncVendorSlug(config.DB, id, row.Title, row.Location); err != nil {
+		log.Printf("writeVendorImportRow slug error for vendor %d: %v", id, err)
+	}
+	indexVendor(context.Background(), id)
+	return created, nil
+}
+
+func saveVendorImportProgress(job *models.VendorImportJob) {
+	errorsJSON, _ := json.Marshal(job.Errors)
+	_, err := config.DB.Exec(`
+		UPDATE vendor_import_jobs
+		SET status=$2, processed_rows=$3, created_rows=$4, updated_rows=$5, failed_rows=$6,
+		    errors=$7, finished_at=$8, updated_at=NOW()
+		WHERE id=$1
+	`, job.ID, job.Status, job.ProcessedRows, job.CreatedRows, job.UpdatedRows, job.FailedRows,
+		errorsJSON, job.FinishedAt)
+	if err != nil {
+		log.Printf("saveVendorImportProgress error for job %d: %v", job.ID, err)
+	}
+}
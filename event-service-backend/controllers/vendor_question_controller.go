@@ -0,0 +1,411 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// questions with this many distinct flags are hidden until an admin reviews them
+const questionAutoHideFlags = 3
+
+// answered questions shown with GET /vendors/:id
+const vendorDetailQuestions = 3
+
+const vendorQuestionSelect = `
+	SELECT q.id, q.vendor_id, COALESCE(q.user_id, 0), COALESCE(u.full_name, ''), q.question, q.answer,
+	       q.answered_at, q.upvotes, q.flag_count, q.hidden, q.created_at, q.updated_at,
+	       EXISTS(SELECT 1 FROM vendor_question_votes qv WHERE qv.question_id = q.id AND qv.user_id = $1)
+	FROM vendor_questions q
+	LEFT JOIN users u ON u.id = q.user_id
+`
+
+func scanVendorQuestion(scanner interface{ Scan(...any) error }, q *models.VendorQuestion) error {
+	return scanner.Scan(
+		&q.ID, &q.VendorID, &q.UserID, &q.AskedBy, &q.Question, &q.Answer,
+		&q.AnsweredAt, &q.Upvotes, &q.FlagCount, &q.Hidden, &q.CreatedAt, &q.UpdatedAt,
+		&q.Upvoted,
+	)
+}
+
+// GET /vendors/:id/questions
+// Public; when a session is present each question reports whether the
+// caller has upvoted it.
+func GetVendorQuestions(c *gin.Context) {
+	vendorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor id"})
+		return
+	}
+
+	viewerID := 0
+	if claims, ok := clerk.SessionClaimsFromContext(c.Request.Context()); ok {
+		config.DB.QueryRow(`SELECT id FROM users WHERE clerk_id=$1`, claims.Subject).Scan(&viewerID)
+	}
+
+	rows, err := config.DB.Query(vendorQuestionSelect+`
+		WHERE q.vendor_id = $2 AND NOT q.hidden
+		ORDER BY q.upvotes DESC, q.created_at DESC
+	`, viewerID, vendorID)
+	if err != nil {
+		log.Printf("GetVendorQuestions query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch questions"})
+		return
+	}
+	defer rows.Close()
+
+	questions := []models.VendorQuestion{}
+	for rows.Next() {
+		var q models.VendorQuestion
+		if err := scanVendorQuestion(rows, &q); err != nil {
+			log.Printf("GetVendorQuestions scan error: %v", err)
+			continue
+		}
+		questions = append(questions, q)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"questions": questions})
+}
+
+// vendorQuestionPreview counts a listing's visible questions and returns the
+// most recently answered ones for the vendor page.
+func vendorQuestionPreview(vendorID int) (int, []models.VendorQuestion, error) {
+	var count int
+	if err := config.DB.QueryRow(
+		`SELECT COUNT(*) FROM vendor_questions WHERE vendor_id=$1 AND NOT hidden`, vendorID,
+	).Scan(&count); err != nil {
+		return 0, nil, err
+	}
+
+	questions := []models.VendorQuestion{}
+	if count == 0 {
+		return 0, questions, nil
+	}
+	rows, err := config.DB.Query(vendorQuestionSelect+`
+		WHERE q.vendor_id = $2 AND NOT q.hidden AND q.answer IS NOT NULL
+		ORDER BY q.answered_at DESC, q.id DESC
+		LIMIT $3
+	`, 0, vendorID, vendorDetailQuestions)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var q models.VendorQuestion
+		if err := scanVendorQuestion(rows, &q); err != nil {
+			return 0, nil, err
+		}
+		questions = append(questions, q)
+	}
+	return count, questions, rows.Err()
+}
+
+// POST /vendors/:id/questions
+// body: { "question": string }
+func AskVendorQuestion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	vendorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor id"})
+		return
+	}
+
+	var input struct {
+		Question string `json:"question"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Question) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question is required"})
+		return
+	}
+	if len(input.Question) > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question must be at most 1000 characters"})
+		return
+	}
+
+	var ownerID int
+	var title string
+	if err := config.DB.QueryRow(`SELECT vendor_id, title FROM vendors WHERE id=$1`, vendorID).Scan(&ownerID, &title); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "vendor not found"})
+		return
+	}
+
+	q := models.VendorQuestion{
+		VendorID: vendorID,
+		UserID:   userID,
+		Question: strings.TrimSpace(input.Question),
+	}
+	err = config.DB.QueryRow(`
+		INSERT INTO vendor_questions (vendor_id, user_id, question, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id, created_at, updated_at
+	`, q.VendorID, q.UserID, q.Question, time.Now()).Scan(&q.ID, &q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		log.Printf("AskVendorQuestion insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save question"})
+		return
+	}
+
+	if ownerID != 0 && ownerID != userID {
+		go utils.NotifyUser(ownerID, "New question on "+title, q.Question, map[string]string{
+			"type":        "vendor_question",
+			"vendor_id":   strconv.Itoa(vendorID),
+			"question_id": strconv.Itoa(q.ID),
+		})
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"question": q})
+}
+
+// POST /vendors/:id/questions/:question_id/answer
+// body: { "answer": string } — only the vendor who owns the listing may answer
+func AnswerVendorQuestion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	q, ok := loadVendorQuestion(c, userID)
+	if !ok {
+		return
+	}
+
+	var ownerID int
+	if err := config.DB.QueryRow(`SELECT vendor_id FROM vendors WHERE id=$1`, q.VendorID).Scan(&ownerID); err != nil || ownerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the vendor can answer this question"})
+		return
+	}
+
+	var input struct {
+		Answer string `json:"answer"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Answer) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "answer is required"})
+		return
+	}
+
+	answer := strings.TrimSpace(input.Answer)
+	now := time.Now()
+	if _, err := config.DB.Exec(`
+		UPDATE vendor_questions SET answer=$2, answered_at=$3, updated_at=$3 WHERE id=$1
+	`, q.ID, answer, now); err != nil {
+		log.Printf("AnswerVendorQuestion update error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save answer"})
+		return
+	}
+	q.Answer = &answer
+	q.AnsweredAt = &now
+	q.UpdatedAt = now
+
+	if q.UserID != 0 {
+		go utils.NotifyUser(q.UserID, "Your question was answered", answer, map[string]string{
+			"type":        "vendor_answer",
+			"vendor_id":   strconv.Itoa(q.VendorID),
+			"question_id": strconv.Itoa(q.ID),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"question": q})
+}
+
+// POST /vendors/:id/questions/:question_id/upvote
+func UpvoteVendorQuestion(c *gin.Context) {
+	setVendorQuestionVote(c, true)
+}
+
+// DELETE /vendors/:id/questions/:question_id/upvote
+func RemoveVendorQuestionUpvote(c *gin.Context) {
+	setVendorQuestionVote(c, false)
+}
+
+func setVendorQuestionVote(c *gin.Context, upvote bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	q, ok := loadVendorQuestion(c, userID)
+	if !ok {
+		return
+	}
+
+	var query string
+	if upvote {
+		query = `INSERT INTO vendor_question_votes (question_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	} else {
+		query = `DELETE FROM vendor_question_votes WHERE question_id=$1 AND user_id=$2`
+	}
+	if _, err := config.DB.Exec(query, q.ID, userID); err != nil {
+		log.Printf("setVendorQuestionVote error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update vote"})
+		return
+	}
+
+	// keep the denormalized counter in step with the votes table
+	if err := config.DB.QueryRow(`
+		UPDATE vendor_questions
+		SET upvotes = (SELECT COUNT(*) FROM vendor_question_votes WHERE question_id=$1)
+		WHERE id=$1
+		RETURNING upvotes
+	`, q.ID).Scan(&q.Upvotes); err != nil {
+		log.Printf("setVendorQuestionVote count error: %v", err)
+	}
+	q.Upvoted = upvote
+
+	c.JSON(http.StatusOK, gin.H{"question": q})
+}
+
+// POST /vendors/:id/questions/:question_id/flag
+// body: { "reason": string }
+func FlagVendorQuestion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	q, ok := loadVendorQuestion(c, userID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	c.ShouldBindJSON(&input)
+
+	if _, err := config.DB.Exec(`
+		INSERT INTO vendor_question_flags (question_id, user_id, reason) VALUES ($1, $2, $3)
+		ON CONFLICT (question_id, user_id) DO UPDATE SET reason = EXCLUDED.reason
+	`, q.ID, userID, strings.TrimSpace(input.Reason)); err != nil {
+		log.Printf("FlagVendorQuestion insert error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not flag question"})
+		return
+	}
+
+	if _, err := config.DB.Exec(`
+		UPDATE vendor_questions
+		SET flag_count = (SELECT COUNT(*) FROM vendor_question_flags WHERE question_id=$1),
+		    hidden = hidden OR (SELECT COUNT(*) FROM vendor_question_flags WHERE question_id=$1) >= $2
+		WHERE id=$1
+	`, q.ID, questionAutoHideFlags); err != nil {
+		log.Printf("FlagVendorQuestion count error: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "question flagged"})
+}
+
+// GET /admin/questions/flagged
+func GetFlaggedVendorQuestions(c *gin.Context) {
+	rows, err := config.DB.Query(vendorQuestionSelect+`
+		WHERE q.flag_count > 0
+		ORDER BY q.flag_count DESC, q.created_at DESC
+	`, 0)
+	if err != nil {
+		log.Printf("GetFlaggedVendorQuestions query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch questions"})
+		return
+	}
+	defer rows.Close()
+
+	questions := []models.VendorQuestion{}
+	for rows.Next() {
+		var q models.VendorQuestion
+		if err := scanVendorQuestion(rows, &q); err != nil {
+			log.Printf("GetFlaggedVendorQuestions scan error: %v", err)
+			continue
+		}
+		questions = append(questions, q)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"questions": questions})
+}
+
+// PATCH /admin/questions/:id
+// body: { "hidden": bool } — clearing hidden also clears the flags
+func ModerateVendorQuestion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var input struct {
+		Hidden *bool `json:"hidden"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.Hidden == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hidden is required"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE vendor_questions SET hidden=$2, updated_at=NOW() WHERE id=$1`, id, *input.Hidden)
+	if err != nil {
+		log.Printf("ModerateVendorQuestion update error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update question"})
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "question not found"})
+		return
+	}
+	if !*input.Hidden {
+		if _, err := tx.Exec(`DELETE FROM vendor_question_flags WHERE question_id=$1`, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not clear flags"})
+			return
+		}
+		if _, err := tx.Exec(`UPDATE vendor_questions SET flag_count=0 WHERE id=$1`, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not clear flags"})
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("question %d updated", id)})
+}
+
+// loadVendorQuestion reads :id and :question_id from the URL and makes sure
+// the question belongs to that vendor and is visible.
+func loadVendorQuestion(c *gin.Context, viewerID int) (models.VendorQuestion, bool) {
+	var q models.VendorQuestion
+
+	vendorID, err1 := strconv.Atoi(c.Param("id"))
+	questionID, err2 := strconv.Atoi(c.Param("question_id"))
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return q, false
+	}
+
+	err := scanVendorQuestion(config.DB.QueryRow(vendorQuestionSelect+`
+		WHERE q.id = $2 AND q.vendor_id = $3 AND NOT q.hidden
+	`, viewerID, questionID, vendorID), &q)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "question not found"})
+		return q, false
+	}
+	if err != nil {
+		log.Printf("loadVendorQuestion query error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+		return q, false
+	}
+	return q, true
+}
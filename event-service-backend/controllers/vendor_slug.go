@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dharmaseervi/event-service-backend/utils"
+)
+
+// dbRunner is satisfied by both *sql.DB and *sql.Tx
+type dbRunner interface {
+	QueryRow(query string, args ...any) *sql.Row
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// vendorSlugBase builds the slug a listing should have from its title and
+// city. The city is taken as the last comma-separated part of the location
+// ("Koramangala, Bangalore" -> "bangalore").
+func vendorSlugBase(title, location string) string {
+	city := ""
+	parts := strings.Split(location, ",")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if p := strings.TrimSpace(parts[i]); p != "" {
+			city = p
+			break
+		}
+	}
+
+	base := utils.Slugify(title, city)
+	if base == "" {
+		base = "vendor"
+	} else if strings.Trim(base, "0123456789") == "" {
+		// GetVendorByID reads a digit-only :id as an id, never as a slug
+		base = "vendor-" + base
+	}
+	return base
+}
+
+// uniqueVendorSlug returns base, or base-2, base-3... whichever is not taken
+// by another listing's current or former slug.
+func uniqueVendorSlug(db dbRunner, base string, vendorID int) (string, error) {
+	for n := 1; ; n++ {
+		slug := base
+		if n > 1 {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		var taken bool
+		err := db.QueryRow(`
+			SELECT EXISTS(SELECT 1 FROM vendors WHERE slug=$1 AND id<>$2)
+			    OR EXISTS(SELECT 1 FROM vendor_slug_history WHERE slug=$1 AND vendor_id<>$2)
+		`, slug, vendorID).Scan(&taken)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+}
+
+// syncVendorSlug gives the listing a slug matching its current title and
+// city. The slug is left alone while the title and city are unchanged, so
+// links stay stable; on a rename the old slug moves to vendor_slug_history.
+func syncVendorSlug(db dbRunner, vendorID int, title, location string) (string, error) {
+	var current sql.NullString
+	if err := db.QueryRow(`SELECT slug FROM vendors WHERE id=$1`, vendorID).Scan(&current); err != nil {
+		return "", err
+	}
+
+	base := vendorSlugBase(title, location)
+	if current.Valid && slugMatchesBase(current.String, base) {
+		return current.String, nil
+	}
+
+	slug, err := uniqueVendorSlug(db, base, vendorID)
+	if err != nil {
+		return "", err
+	}
+
+	if current.Valid && current.String != "" {
+		if _, err := db.Exec(`
+			INSERT INTO vendor_slug_history (slug, vendor_id) VALUES ($1, $2)
+			ON CONFLICT (slug) DO NOTHING
+		`, current.String, vendorID); err != nil {
+			return "", err
+		}
+	}
+	// renaming back to an old name reclaims that slug
+	if _, err := db.Exec(`DELETE FROM vendor_slug_history WHERE slug=$1`, slug); err != nil {
+		return "", err
+	}
+	if _, err := db.Exec(`UPDATE vendors SET slug=$2 WHERE id=$1`, vendorID, slug); err != nil {
+		return "", err
+	}
+	return slug, nil
+}
+
+var slugSuffix = regexp.MustCompile(`^-\d+$`)
+
+func slugMatchesBase(slug, base string) bool {
+	return slug == base || (strings.HasPrefix(slug, base) && slugSuffix.MatchString(slug[len(base):]))
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/controllers"
+)
+
+// Start launches the periodic background jobs. They stop when ctx is cancelled.
+func Start(ctx context.Context) {
+	go every(ctx, 15*time.Minute, "refresh search vocabulary", RefreshSearchVocabulary)
+	go every(ctx, time.Hour, "saved search alerts", controllers.RunSavedSearchAlerts)
+	go every(ctx, time.Hour, "saved vendor digests", controllers.RunSavedVendorDigests)
+	go every(ctx, time.Hour, "checklist reminders", controllers.RunChecklistReminders)
+	go every(ctx, 24*time.Hour, "prune search logs", PruneSearchLogs)
+	go every(ctx, 5*time.Minute, "fail stale vendor imports", FailStaleVendorImports)
+}
+
+// every runs fn once per interval, starting after the first interval. A
+// failing run is logged and retried on the next tick.
+func every(ctx context.Context, interval time.Duration, name string, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := fn(ctx); err != nil {
+				log.Printf("Job %q failed: %v", name, err)
+				continue
+			}
+			log.Printf("Job %q finished in %s", name, time.Since(start).Round(time.Millisecond))
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+)
+
+// raw search logs are kept this long unless SEARCH_LOG_RETENTION_DAYS says otherwise
+const defaultSearchLogRetentionDays = 90
+
+// PruneSearchLogs deletes search logs (and their clicks) older than the
+// retention period.
+func PruneSearchLogs(ctx context.Context) error {
+	days := defaultSearchLogRetentionDays
+	if n, err := strconv.Atoi(os.Getenv("SEARCH_LOG_RETENTION_DAYS")); err == nil && n > 0 {
+		days = n
+	}
+	_, err := config.DB.ExecContext(ctx, `DELETE FROM search_logs WHERE created_at < NOW() - make_interval(days => $1)`, days)
+	return err
+}
@@ -0,0 +1,14 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+)
+
+// RefreshSearchVocabulary rebuilds the word list behind "did you mean" so new
+// and renamed listings are picked up.
+func RefreshSearchVocabulary(ctx context.Context) error {
+	_, err := config.DB.ExecContext(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY search_vocabulary`)
+	return err
+}
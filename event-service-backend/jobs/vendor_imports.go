@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+)
+
+// an unfinished import that saved no progress for this long lost its runner,
+// e.g. to a restart; running imports save every few dozen rows
+const staleVendorImportMinutes = 10
+
+// FailStaleVendorImports marks imports whose runner went away as failed so
+// clients polling them stop waiting.
+func FailStaleVendorImports(ctx context.Context) error {
+	res, err := config.DB.ExecContext(ctx, `
+		UPDATE vendor_import_jobs
+		SET status = 'failed', finished_at = NOW(), updated_at = NOW()
+		WHERE status IN ('pending', 'running')
+		  AND updated_at < NOW() - make_interval(mins => $1)
+	`, staleVendorImportMinutes)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("Marked %d stale vendor imports as failed", n)
+	}
+	return nil
+}
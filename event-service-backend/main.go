@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -9,7 +10,10 @@ import (
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/jobs"
+	"github.com/dharmaseervi/event-service-backend/realtime"
 	"github.com/dharmaseervi/event-service-backend/routes"
+	"github.com/dharmaseervi/event-service-backend/search"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,6 +22,8 @@ func main() {
 	config.InitDB()
 	defer config.CloseDB()
 
+	search.Vendors = search.NewPostgres(config.DB)
+
 	clerk.SetKey(os.Getenv("CLERK_SECRET_KEY"))
 
 	// Set Gin mode
@@ -33,10 +39,24 @@ func main() {
 	routes.SetupVendorRoutes(router)
 	routes.SetupSearchRoutes(router)
 	routes.SetupSavedVendorRoutes(router)
+	routes.SetupSavedSearchRoutes(router)
 	routes.SetupBookingRoutes(router)
+	routes.SetupEventRoutes(router)
+	routes.SetupPlannerRoutes(router)
+	routes.SetupMessageRoutes(router)
+	routes.SetupRealtimeRoutes(router)
 	routes.SeTupSendNotification(router)
 	routes.SetupUnavailableDateRoutes(router)
 	routes.SetupVendorDealsRoutes(router)
+	routes.SetupAdminRoutes(router)
+	routes.SetupExportRoutes(router)
+
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobs.Start(jobsCtx)
+
+	// Realtime fan-out across instances
+	go realtime.Listen(jobsCtx, config.ConnString())
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -69,6 +89,7 @@ func main() {
 	// Block until we receive a signal
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Close database connection
 	config.CloseDB()
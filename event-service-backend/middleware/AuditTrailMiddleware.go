@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditTrailMiddleware records every successful change made through the
+// routes it guards. Handlers opt in by setting "audit_actor_id",
+// "audit_client_id" and "audit_event_id" on the Gin context once they know
+// whose data the request touches; requests that never set them are skipped.
+func AuditTrailMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Request.Method == http.MethodGet || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		actorID := c.GetInt("audit_actor_id")
+		if actorID == 0 {
+			return
+		}
+		var eventID *int
+		if id := c.GetInt("audit_event_id"); id != 0 {
+			eventID = &id
+		}
+
+		details := map[string]any{}
+		for _, p := range c.Params {
+			details[p.Key] = p.Value
+		}
+		utils.RecordAudit(actorID, c.GetInt("audit_client_id"), eventID, c.Request.Method+" "+c.FullPath(), details)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	clerkhttp "github.com/clerk/clerk-sdk-go/v2/http"
+	"github.com/gin-gonic/gin"
+)
+
+// OptionalClerkAuthMiddleware is ClerkAuthMiddleware for public routes: a
+// valid session token puts the claims on the request context, while a
+// missing, expired or invalid one lets the request through anonymously
+// instead of answering 401.
+func OptionalClerkAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.Request = c.Request.WithContext(r.Context())
+			c.Next()
+		})
+		// on a failed verification carry on without claims
+		handler := clerkhttp.WithHeaderAuthorization(
+			clerkhttp.AuthorizationFailureHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.Next()
+			})),
+		)(next)
+
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
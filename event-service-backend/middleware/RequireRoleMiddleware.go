@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRoleMiddleware only lets the request through when the signed-in user
+// has one of the given roles ('user', 'vendor', 'planner', 'admin'). It must run after
+// ClerkAuthMiddleware. The local user id and role are stored on the Gin
+// context as "user_id" and "user_role".
+func RequireRoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := clerk.SessionClaimsFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var userID int
+		var role string
+		err := config.DB.QueryRow(
+			`SELECT id, COALESCE(role, 'user') FROM users WHERE clerk_id=$1`, claims.Subject,
+		).Scan(&userID, &role)
+		if err == sql.ErrNoRows {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+			return
+		}
+
+		for _, r := range roles {
+			if r == role {
+				c.Set("user_id", userID)
+				c.Set("user_role", role)
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	}
+}
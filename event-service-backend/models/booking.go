@@ -5,12 +5,14 @@ import (
 )
 
 type Booking struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	VendorID  int       `json:"vendor_id"`
-	EventDate time.Time `json:"event_date"`
-	Status    string    `json:"status"` // e.g., pending, confirmed, cancelled
-	Notes     string    `json:"notes"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	VendorID   int       `json:"vendor_id"`
+	EventID    *int      `json:"event_id"` // events.id, when planned as part of one
+	EventDate  time.Time `json:"event_date"`
+	Status     string    `json:"status"`      // e.g., pending, confirmed, cancelled
+	TotalPrice *float64  `json:"total_price"` // quoted or agreed price
+	Notes      string    `json:"notes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
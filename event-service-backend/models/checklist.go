@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// ChecklistTemplate is a task every event of a type gets, due a number of
+// days before the event starts.
+type ChecklistTemplate struct {
+	ID         int       `json:"id"`
+	EventType  string    `json:"event_type"`
+	Title      string    `json:"title"`
+	Category   *string   `json:"category"`    // vendor category whose confirmed booking completes it
+	OffsetDays int       `json:"offset_days"` // days before start_date
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// EventTask is a checklist item of an event, from a template or added by
+// the user.
+type EventTask struct {
+	ID         int        `json:"id"`
+	EventID    int        `json:"event_id"`
+	TemplateID *int       `json:"template_id"` // nil for custom tasks
+	Title      string     `json:"title"`
+	Category   *string    `json:"category"`
+	DueDate    string     `json:"due_date"` // YYYY-MM-DD
+	Done       bool       `json:"done"`
+	DoneAt     *time.Time `json:"done_at"`
+	BookingID  *int       `json:"booking_id"` // set when a confirmed booking completed it
+	Overdue    bool       `json:"overdue"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// Event is something a user is planning. Bookings can be attached to it and
+// a saved-vendor collection can serve as its shortlist.
+type Event struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"user_id"`
+	PlannerID    *int      `json:"planner_id"` // planner managing it for the owner
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`       // see EventTypes
+	StartDate    string    `json:"start_date"` // YYYY-MM-DD
+	EndDate      string    `json:"end_date"`   // same as start_date for one-day events
+	City         string    `json:"city"`
+	GuestCount   *int      `json:"guest_count"`
+	Budget       *float64  `json:"budget"`
+	CollectionID *int      `json:"collection_id"` // shortlist; nil when none is linked
+	Status       string    `json:"status"`        // planning, completed or cancelled
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// EventTypes mirrors the CHECK constraint on events.type
+var EventTypes = []string{"wedding", "engagement", "birthday", "anniversary", "corporate", "party", "other"}
+
+// EventStatuses mirrors the CHECK constraint on events.status
+var EventStatuses = []string{"planning", "completed", "cancelled"}
+
+// EventBooking is a booking shown in an event overview
+type EventBooking struct {
+	Booking
+	Vendor VendorListing `json:"vendor"`
+}
+
+// EventOverview is everything the planning screen needs for one event.
+type EventOverview struct {
+	Event
+	DaysUntil     int               `json:"days_until"`     // negative once the event has started
+	BookingCounts map[string]int    `json:"booking_counts"` // by booking status
+	Headcount     GuestHeadcount    `json:"headcount"`      // from RSVPs, for the caterer
+	Bookings      []EventBooking    `json:"bookings"`
+	SavedVendors  []SavedVendorItem `json:"saved_vendors"` // from the linked collection
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// EventExpense is spend on an event that did not come from a booking.
+type EventExpense struct {
+	ID          int       `json:"id"`
+	EventID     int       `json:"event_id"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	SpentOn     string    `json:"spent_on"` // YYYY-MM-DD
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BudgetLine is spend against budget for one category, or for the whole
+// event in the totals. Quoted is pending bookings; confirmed is confirmed
+// and completed bookings; cancelled bookings are left out.
+type BudgetLine struct {
+	Category  string   `json:"category,omitempty"`
+	Budget    *float64 `json:"budget"` // nil when no budget was set
+	Quoted    float64  `json:"quoted"`
+	Confirmed float64  `json:"confirmed"`
+	Expenses  float64  `json:"expenses"`
+	Committed float64  `json:"committed"` // quoted + confirmed + expenses
+	Remaining *float64 `json:"remaining"` // budget - committed, nil without a budget
+	Overspend float64  `json:"overspend"` // committed over budget, 0 when within it
+}
+
+// EventBudget is the budget report for an event.
+type EventBudget struct {
+	EventID    int          `json:"event_id"`
+	Categories []BudgetLine `json:"categories"`
+	Total      BudgetLine   `json:"total"` // budget is the event's overall budget when set
+}
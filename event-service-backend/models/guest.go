@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// EventGuest is someone on an event's guest list and their RSVP.
+type EventGuest struct {
+	ID              int        `json:"id"`
+	EventID         int        `json:"event_id"`
+	Name            string     `json:"name"`
+	Email           *string    `json:"email"`
+	Phone           *string    `json:"phone"`
+	Group           string     `json:"group"`
+	PlusOnesAllowed int        `json:"plus_ones_allowed"`
+	RSVPToken       string     `json:"rsvp_token"`
+	RSVPStatus      string     `json:"rsvp_status"` // pending, attending or declined
+	PlusOnes        int        `json:"plus_ones"`
+	MealPreference  *string    `json:"meal_preference"` // see MealPreferences
+	RSVPNote        string     `json:"rsvp_note"`
+	InvitedAt       *time.Time `json:"invited_at"`
+	RespondedAt     *time.Time `json:"responded_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// MealPreferences mirrors the CHECK constraint on event_guests.meal_preference
+var MealPreferences = []string{"veg", "non_veg", "vegan", "jain", "other"}
+
+// GuestHeadcount sums up the RSVPs of an event. Guests count their
+// invitations; people count attending guests plus their plus-ones, which is
+// the number to give the caterer.
+type GuestHeadcount struct {
+	Guests    int            `json:"guests"`
+	Pending   int            `json:"pending"`
+	Attending int            `json:"attending"`
+	Declined  int            `json:"declined"`
+	People    int            `json:"people"`
+	Meals     map[string]int `json:"meals"`    // people by meal preference; "unknown" when not given
+	ByGroup   map[string]int `json:"by_group"` // people by guest group
+}
+
+// RSVPInvite is what the public RSVP page shows a guest.
+type RSVPInvite struct {
+	EventName       string  `json:"event_name"`
+	EventType       string  `json:"event_type"`
+	StartDate       string  `json:"start_date"`
+	EndDate         string  `json:"end_date"`
+	City            string  `json:"city"`
+	HostName        string  `json:"host_name"`
+	GuestName       string  `json:"guest_name"`
+	PlusOnesAllowed int     `json:"plus_ones_allowed"`
+	RSVPStatus      string  `json:"rsvp_status"`
+	PlusOnes        int     `json:"plus_ones"`
+	MealPreference  *string `json:"meal_preference"`
+	Open            bool    `json:"open"` // false once the event is over or cancelled
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Conversation is a thread between a customer and the vendor behind a
+// listing, optionally about one booking.
+type Conversation struct {
+	ID            int                       `json:"id"`
+	VendorID      int                       `json:"vendor_id"` // references vendors.id
+	VendorTitle   string                    `json:"vendor_title"`
+	UserID        int                       `json:"user_id"` // the customer
+	CustomerName  string                    `json:"customer_name"`
+	BookingID     *int                      `json:"booking_id"`
+	Role          string                    `json:"role"`         // the caller's side: customer or vendor
+	UnreadCount   int                       `json:"unread_count"` // for the caller
+	LastMessage   *Message                  `json:"last_message"`
+	LastMessageAt *time.Time                `json:"last_message_at"`
+	Participants  []ConversationParticipant `json:"participants,omitempty"`
+	CreatedAt     time.Time                 `json:"created_at"`
+}
+
+// ConversationParticipant is one side of a conversation and how far they
+// have read.
+type ConversationParticipant struct {
+	UserID            int        `json:"user_id"`
+	Name              string     `json:"name"`
+	Role              string     `json:"role"`
+	UnreadCount       int        `json:"unread_count"`
+	LastReadMessageID int        `json:"last_read_message_id"`
+	LastReadAt        *time.Time `json:"last_read_at"`
+}
+
+type Message struct {
+	ID             int                 `json:"id"`
+	ConversationID int                 `json:"conversation_id"`
+	SenderID       *int                `json:"sender_id"`
+	SenderName     string              `json:"sender_name"`
+	Body           string              `json:"body"`
+	Attachments    []MessageAttachment `json:"attachments"`
+	Read           bool                `json:"read"` // by everyone else in the conversation
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// MessageAttachment points at a file the app uploaded to storage.
+type MessageAttachment struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
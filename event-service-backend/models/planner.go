@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PlannerClient links a planner to a client they plan for.
+type PlannerClient struct {
+	PlannerID        int        `json:"planner_id"`
+	PlannerName      string     `json:"planner_name"`
+	ClientID         int        `json:"client_id"`
+	ClientName       string     `json:"client_name"`
+	ClientEmail      string     `json:"client_email"`
+	Status           string     `json:"status"`            // pending until the client accepts, then active
+	RequiresApproval bool       `json:"requires_approval"` // client okays bookings and saves first
+	CreatedAt        time.Time  `json:"created_at"`
+	AcceptedAt       *time.Time `json:"accepted_at"`
+}
+
+// PlannerRequest is a booking or vendor save a planner wants to make for a
+// client, waiting for the client's approval.
+type PlannerRequest struct {
+	ID          int             `json:"id"`
+	PlannerID   int             `json:"planner_id"`
+	PlannerName string          `json:"planner_name"`
+	ClientID    int             `json:"client_id"`
+	EventID     *int            `json:"event_id"`
+	Kind        string          `json:"kind"` // booking or save_vendor
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"` // pending, approved or rejected
+	ResultID    *int            `json:"result_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	DecidedAt   *time.Time      `json:"decided_at"`
+}
+
+// PlannerEventSummary is one row of the planner dashboard.
+type PlannerEventSummary struct {
+	Event
+	ClientName        string   `json:"client_name"`
+	PendingBookings   int      `json:"pending_bookings"`
+	ConfirmedBookings int      `json:"confirmed_bookings"`
+	OverdueTasks      int      `json:"overdue_tasks"`
+	OpenTasks         int      `json:"open_tasks"`
+	AttendingPeople   int      `json:"attending_people"`
+	Committed         float64  `json:"committed"` // bookings and expenses against the budget
+	PendingRequests   int      `json:"pending_requests"`
+	DaysUntil         int      `json:"days_until"`
+	BudgetLeft        *float64 `json:"budget_left"`
+}
+
+// AuditEntry is one action in the audit trail.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	ActorID   *int            `json:"actor_id"`
+	ActorName string          `json:"actor_name"`
+	ClientID  *int            `json:"client_id"`
+	EventID   *int            `json:"event_id"`
+	Action    string          `json:"action"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"created_at"`
+}
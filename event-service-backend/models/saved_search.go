@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+type SavedSearch struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Name      string     `json:"name"`
+	Query     string     `json:"query"` // /search/vendors query string
+	Notify    bool       `json:"notify"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
 
 type SavedVendor struct {
 	ID        int       `json:"id"`
@@ -8,3 +12,73 @@ type SavedVendor struct {
 	VendorID  int       `json:"vendor_id"` // this references `vendors.id`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// SavedCollection is a named list of saved vendors. ID 0 is the default
+// collection: everything saved without picking one.
+type SavedCollection struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Default     bool       `json:"default"`
+	VendorCount int        `json:"vendor_count"`
+	Role        string     `json:"role"`     // the caller's role: owner, editor or viewer
+	OwnerID     int        `json:"owner_id"` // users.id
+	OwnerName   string     `json:"owner_name"`
+	CreatedAt   *time.Time `json:"created_at"` // nil for the default collection
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// SavedVendorItem is a vendor in a collection with the user's own notes.
+// In shared collections it also carries who added it and the collaborators'
+// votes and comment count.
+type SavedVendorItem struct {
+	ID           int            `json:"id"`
+	CollectionID int            `json:"collection_id"` // 0 for the default collection
+	Notes        string         `json:"notes"`
+	Tags         pq.StringArray `json:"tags"`
+	Position     *int           `json:"position"`
+	AddedBy      *int           `json:"added_by"` // users.id; nil for items saved before sharing existed
+	AddedByName  string         `json:"added_by_name"`
+	Votes        int            `json:"votes"`
+	Voted        bool           `json:"voted"` // by the requesting user
+	Comments     int            `json:"comments"`
+	SavedAt      time.Time      `json:"saved_at"`
+	Vendor       *VendorListing `json:"vendor,omitempty"`
+}
+
+// CollectionMember is someone a collection is shared with, or its owner.
+type CollectionMember struct {
+	UserID   int       `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"` // owner, editor or viewer
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// CollectionInvite lets someone join a collection. Email-less invites work
+// as a link for whoever has the token.
+type CollectionInvite struct {
+	ID           int        `json:"id"`
+	CollectionID int        `json:"collection_id"`
+	Token        string     `json:"token"`
+	Email        *string    `json:"email"`
+	Role         string     `json:"role"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type SavedVendorComment struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SavedVendorAlerts is a user's opt-in for the daily digest of new deals
+// and price drops on their saved vendors.
+type SavedVendorAlerts struct {
+	NewDeals     bool       `json:"new_deals"`
+	PriceDrops   bool       `json:"price_drops"`
+	LastDigestAt *time.Time `json:"last_digest_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// FacetCount is one entry of a search facet, e.g. {"value": "catering", "count": 12}
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// VendorSuggestion is a typeahead hit for a listing title
+type VendorSuggestion struct {
+	ID         int    `json:"id"`
+	Title      string `json:"title"`
+	Slug       string `json:"slug"`
+	Category   string `json:"category"`
+	City       string `json:"city"`
+	Popularity int    `json:"popularity"`
+}
+
+// SearchSuggestions is the response of GET /search/suggest. Categories and
+// cities use Count for the number of listings.
+type SearchSuggestions struct {
+	Vendors    []VendorSuggestion `json:"vendors"`
+	Categories []FacetCount       `json:"categories"`
+	Cities     []FacetCount       `json:"cities"`
+}
+
+// SearchQueryStats is one row of the search analytics reports, grouped by
+// normalized query.
+type SearchQueryStats struct {
+	Query           string  `json:"query"`
+	Searches        int     `json:"searches"`
+	Users           int     `json:"users"`
+	ZeroResults     int     `json:"zero_results"`
+	AvgResults      float64 `json:"avg_results"`
+	AvgLatencyMs    float64 `json:"avg_latency_ms"`
+	Clicks          int     `json:"clicks"`
+	ClickedSearches int     `json:"clicked_searches"`
+	ClickThrough    float64 `json:"click_through"` // share of searches with at least one click
+}
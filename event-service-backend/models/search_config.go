@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type SearchSynonym struct {
+	ID        int            `json:"id"`
+	Term      string         `json:"term"`
+	Synonyms  pq.StringArray `json:"synonyms"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// SearchSettings tunes vendor search ranking. Weights apply to the title,
+// category, description and location parts of the search vector.
+type SearchSettings struct {
+	WeightTitle       float64   `json:"weight_title"`
+	WeightCategory    float64   `json:"weight_category"`
+	WeightDescription float64   `json:"weight_description"`
+	WeightLocation    float64   `json:"weight_location"`
+	RatingBoost       float64   `json:"rating_boost"`
+	FeaturedBoost     float64   `json:"featured_boost"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
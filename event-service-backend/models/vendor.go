@@ -7,16 +7,26 @@ import (
 )
 
 type VendorListing struct {
-	ID          int            `json:"id"`
-	VendorID    int            `json:"vendor_id"`
-	Title       string         `json:"title"`
-	Description string         `json:"description"`
-	Category    string         `json:"category"` // 'venue', 'catering', 'decor', 'photography'
-	PriceRange  string         `json:"price_range"`
-	Location    string         `json:"location"`
-	Photos      pq.StringArray `json:"photos" gorm:"type:text[]"`
-	Rating      float64        `json:"rating"`   // e.g., 4.7
-	Featured    bool           `json:"featured"` // featured = true/false
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID                int            `json:"id"`
+	VendorID          int            `json:"vendor_id"`
+	Title             string         `json:"title"`
+	Slug              string         `json:"slug"`
+	Description       string         `json:"description"`
+	Category          string         `json:"category"` // 'venue', 'catering', 'decor', 'photography'
+	PriceRange        string         `json:"price_range"`
+	Location          string         `json:"location"`
+	City              string         `json:"city,omitempty"` // derived from location by the database
+	Latitude          *float64       `json:"latitude"`
+	Longitude         *float64       `json:"longitude"`
+	Photos            pq.StringArray `json:"photos" gorm:"type:text[]"`
+	Rating            float64        `json:"rating"`                        // e.g., 4.7
+	Featured          bool           `json:"featured"`                      // featured = true/false
+	Language          string         `json:"language"`                      // listing language code, e.g. 'en', 'hi'
+	ExternalRef       string         `json:"external_ref,omitempty"`        // partner's own id, set by bulk imports
+	NextAvailableDate string         `json:"next_available_date,omitempty"` // YYYY-MM-DD, set by search on request
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
 }
+
+// VendorCategories mirrors the CHECK constraint on vendors.category
+var VendorCategories = []string{"venue", "catering", "decor", "photography", "entertainment", "florist", "planning"}
@@ -0,0 +1,19 @@
+package models
+
+// VendorComparison is one column of the side-by-side comparison table.
+// The schema has no reviews, packages or cancellation policies yet, so
+// review_count, packages and cancellation_policy are always null; they are
+// in the response so clients can lay out the full table and fill them in
+// once the data exists.
+type VendorComparison struct {
+	Vendor             VendorListing `json:"vendor"`
+	PriceMin           *float64      `json:"price_min"` // parsed from price_range
+	PriceMax           *float64      `json:"price_max"`
+	Rating             float64       `json:"rating"`
+	ReviewCount        *int          `json:"review_count"`
+	Packages           []string      `json:"packages"`
+	ActiveDeals        []VendorDeal  `json:"active_deals"`
+	Available          *bool         `json:"available"` // on the requested date; null without one
+	CancellationPolicy *string       `json:"cancellation_policy"`
+	DistanceKm         *float64      `json:"distance_km"` // from the requested lat/lng
+}
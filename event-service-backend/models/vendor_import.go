@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+type VendorImportJob struct {
+	ID            int                    `json:"id"`
+	CreatedBy     int                    `json:"created_by"`
+	Format        string                 `json:"format"` // 'csv' or 'jsonl'
+	DryRun        bool                   `json:"dry_run"`
+	Upsert        bool                   `json:"upsert"`
+	Status        string                 `json:"status"` // pending, running, completed, failed
+	TotalRows     int                    `json:"total_rows"`
+	ProcessedRows int                    `json:"processed_rows"`
+	CreatedRows   int                    `json:"created_rows"`
+	UpdatedRows   int                    `json:"updated_rows"`
+	FailedRows    int                    `json:"failed_rows"`
+	Errors        []VendorImportRowError `json:"errors"`
+	CreatedAt     time.Time              `json:"created_at"`
+	FinishedAt    *time.Time             `json:"finished_at"`
+}
+
+// VendorImportRowError is one entry of the per-row error report. Row is
+// 1-based and counts data rows only (the CSV header is not a row).
+type VendorImportRowError struct {
+	Row         int    `json:"row"`
+	ExternalRef string `json:"external_ref,omitempty"`
+	Error       string `json:"error"`
+}
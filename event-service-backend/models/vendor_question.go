@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+type VendorQuestion struct {
+	ID         int        `json:"id"`
+	VendorID   int        `json:"vendor_id"` // references vendors.id
+	UserID     int        `json:"user_id"`
+	AskedBy    string     `json:"asked_by"`
+	Question   string     `json:"question"`
+	Answer     *string    `json:"answer"`
+	AnsweredAt *time.Time `json:"answered_at"`
+	Upvotes    int        `json:"upvotes"`
+	Upvoted    bool       `json:"upvoted"` // by the requesting user
+	FlagCount  int        `json:"flag_count"`
+	Hidden     bool       `json:"hidden"` // hidden by moderation
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// VendorDetail is a listing as GET /vendors/:id shows it, with a preview of
+// its Q&A. The full list is at GET /vendors/:id/questions.
+type VendorDetail struct {
+	VendorListing
+	QuestionCount   int              `json:"question_count"` // visible questions, answered or not
+	LatestQuestions []VendorQuestion `json:"latest_questions"`
+}
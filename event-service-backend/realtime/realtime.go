@@ -0,0 +1,163 @@
+// Package realtime pushes live updates to signed-in users. Every server
+// instance LISTENs on one Postgres channel; anything published there, by Go
+// code through Publish or by database triggers through pg_notify, reaches
+// the users' streams on whichever instance they are connected to.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres notification channel. Payloads are JSON:
+// { "user_ids": [1, 2], "type": "booking_status", "data": {...} }.
+const Channel = "realtime"
+
+const (
+	// events a slow stream may fall behind by before new ones are dropped
+	subscriberBuffer = 32
+	// Postgres rejects notification payloads from 8000 bytes on
+	maxPayload = 7999
+	// how often an idle LISTEN connection is checked
+	listenerPing = 90 * time.Second
+)
+
+// ErrPayloadTooLarge is returned for events that do not fit in a
+// notification; send ids and let clients fetch the rest.
+var ErrPayloadTooLarge = errors.New("realtime event too large")
+
+// Event is one update on a user's stream.
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type envelope struct {
+	UserIDs []int `json:"user_ids"`
+	Event
+}
+
+var hub = struct {
+	sync.Mutex
+	subs map[int]map[chan Event]struct{}
+}{subs: map[int]map[chan Event]struct{}{}}
+
+// Subscribe opens a stream of the user's events on this instance. Call the
+// returned func to close it.
+func Subscribe(userID int) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	hub.Lock()
+	if hub.subs[userID] == nil {
+		hub.subs[userID] = map[chan Event]struct{}{}
+	}
+	hub.subs[userID][ch] = struct{}{}
+	hub.Unlock()
+
+	return ch, func() {
+		hub.Lock()
+		delete(hub.subs[userID], ch)
+		if len(hub.subs[userID]) == 0 {
+			delete(hub.subs, userID)
+		}
+		hub.Unlock()
+	}
+}
+
+// Publish sends an event to the users' streams on every instance, this one
+// included. data must marshal to JSON.
+func Publish(userIDs []int, eventType string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(envelope{UserIDs: userIDs, Event: Event{Type: eventType, Data: raw}})
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		return ErrPayloadTooLarge
+	}
+	_, err = config.DB.Exec(`SELECT pg_notify($1, $2)`, Channel, string(payload))
+	return err
+}
+
+// deliver hands an event to the local streams of its users without
+// waiting on slow readers.
+func deliver(userIDs []int, e Event) {
+	hub.Lock()
+	defer hub.Unlock()
+	for _, userID := range userIDs {
+		for ch := range hub.subs[userID] {
+			select {
+			case ch <- e:
+			default:
+				log.Printf("realtime: dropped %s event for user %d, stream is behind", e.Type, userID)
+			}
+		}
+	}
+}
+
+// broadcast hands an event to every local stream.
+func broadcast(e Event) {
+	hub.Lock()
+	userIDs := make([]int, 0, len(hub.subs))
+	for userID := range hub.subs {
+		userIDs = append(userIDs, userID)
+	}
+	hub.Unlock()
+	deliver(userIDs, e)
+}
+
+// Listen relays notifications on Channel to local streams until ctx is
+// cancelled. Notifications sent while the connection was down are lost, so
+// after a reconnect every stream gets a "resync" event telling the app to
+// refetch.
+func Listen(ctx context.Context, connString string) {
+	listener := pq.NewListener(connString, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("realtime listener: %v", err)
+		}
+		if ev == pq.ListenerEventReconnected {
+			broadcast(Event{Type: "resync", Data: json.RawMessage(`{}`)})
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		log.Printf("realtime: could not listen on %q: %v", Channel, err)
+		return
+	}
+	log.Printf("realtime: listening on %q", Channel)
+
+	ping := time.NewTicker(listenerPing)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			// nil after a reconnect; the callback already asked for a resync
+			if n == nil {
+				continue
+			}
+			var env envelope
+			if err := json.Unmarshal([]byte(n.Extra), &env); err != nil {
+				log.Printf("realtime: bad payload: %v", err)
+				continue
+			}
+			if env.Data == nil {
+				env.Data = json.RawMessage(`{}`)
+			}
+			deliver(env.UserIDs, env.Event)
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
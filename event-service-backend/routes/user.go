@@ -20,15 +20,28 @@ func SetupVendorRoutes(router *gin.Engine) {
 		vendorRoutes.POST("/", controllers.CreateVendor)
 		vendorRoutes.GET("/", controllers.GetAllVendors)
 		vendorRoutes.GET("/:id", controllers.GetVendorByID)
+		vendorRoutes.PUT("/:id", middleware.ClerkAuthMiddleware(), controllers.UpdateVendor)
 		vendorRoutes.GET("/featured", controllers.GetFeaturedVendors)
 		vendorRoutes.GET("/recommended", controllers.GetRecommendedVendors)
+		vendorRoutes.GET("/compare", controllers.CompareVendors)
+
+		// Q&A on a listing; reading is public, everything else needs a session
+		auth := middleware.ClerkAuthMiddleware()
+		vendorRoutes.GET("/:id/questions", middleware.OptionalClerkAuthMiddleware(), controllers.GetVendorQuestions)
+		vendorRoutes.POST("/:id/questions", auth, controllers.AskVendorQuestion)
+		vendorRoutes.POST("/:id/questions/:question_id/answer", auth, controllers.AnswerVendorQuestion)
+		vendorRoutes.POST("/:id/questions/:question_id/upvote", auth, controllers.UpvoteVendorQuestion)
+		vendorRoutes.DELETE("/:id/questions/:question_id/upvote", auth, controllers.RemoveVendorQuestionUpvote)
+		vendorRoutes.POST("/:id/questions/:question_id/flag", auth, controllers.FlagVendorQuestion)
 	}
 }
 
 func SetupSearchRoutes(router *gin.Engine) {
 	searchRoutes := router.Group("/search")
 	{
-		searchRoutes.GET("/vendors", controllers.SearchVendors)
+		// auth is optional here; it only lets analytics tell users apart
+		searchRoutes.GET("/vendors", middleware.OptionalClerkAuthMiddleware(), controllers.SearchVendors)
+		searchRoutes.GET("/suggest", controllers.SuggestSearch)
 	}
 }
 
@@ -37,7 +50,42 @@ func SetupSavedVendorRoutes(r *gin.Engine) {
 	{
 		g.GET("/me", controllers.GetMySavedVendors)
 		g.POST("/", controllers.SaveVendor)
+		g.PATCH("/:vendor_id", controllers.UpdateSavedVendor)
 		g.DELETE("/:vendor_id", controllers.UnsaveVendor)
+		g.GET("/alerts", controllers.GetSavedVendorAlerts)
+		g.PUT("/alerts", controllers.UpdateSavedVendorAlerts)
+
+		g.GET("/collections", controllers.GetMySavedCollections)
+		g.POST("/collections", controllers.CreateSavedCollection)
+		g.PATCH("/collections/:id", controllers.RenameSavedCollection)
+		g.DELETE("/collections/:id", controllers.DeleteSavedCollection)
+		g.GET("/collections/:id/vendors", controllers.GetSavedCollectionVendors)
+		g.PUT("/collections/:id/order", controllers.ReorderSavedCollection)
+
+		// sharing
+		g.POST("/collections/:id/invites", controllers.CreateCollectionInvite)
+		g.GET("/collections/:id/invites", controllers.GetCollectionInvites)
+		g.DELETE("/collections/:id/invites/:invite_id", controllers.RevokeCollectionInvite)
+		g.POST("/invites/:token/accept", controllers.AcceptCollectionInvite)
+		g.GET("/collections/:id/members", controllers.GetCollectionMembers)
+		g.PATCH("/collections/:id/members/:user_id", controllers.UpdateCollectionMember)
+		g.DELETE("/collections/:id/members/:user_id", controllers.RemoveCollectionMember)
+		g.POST("/collections/:id/vendors", controllers.AddCollectionVendor)
+		g.DELETE("/collections/:id/vendors/:vendor_id", controllers.RemoveCollectionVendor)
+		g.GET("/collections/:id/vendors/:vendor_id/comments", controllers.GetSavedVendorComments)
+		g.POST("/collections/:id/vendors/:vendor_id/comments", controllers.AddSavedVendorComment)
+		g.POST("/collections/:id/vendors/:vendor_id/vote", controllers.VoteSavedVendor)
+		g.DELETE("/collections/:id/vendors/:vendor_id/vote", controllers.UnvoteSavedVendor)
+	}
+}
+
+func SetupSavedSearchRoutes(r *gin.Engine) {
+	g := r.Group("/saved-searches", middleware.ClerkAuthMiddleware())
+	{
+		g.GET("/", controllers.GetMySavedSearches)
+		g.POST("/", controllers.CreateSavedSearch)
+		g.PATCH("/:id", controllers.UpdateSavedSearch)
+		g.DELETE("/:id", controllers.DeleteSavedSearch)
 	}
 }
 
@@ -49,6 +97,86 @@ func SetupBookingRoutes(r *gin.Engine) {
 	}
 }
 
+func SetupEventRoutes(r *gin.Engine) {
+	events := r.Group("/events", middleware.ClerkAuthMiddleware(), middleware.AuditTrailMiddleware())
+	{
+		events.POST("/", controllers.CreateEvent)
+		events.GET("/", controllers.GetMyEvents)
+		events.GET("/:id", controllers.GetEvent)
+		events.PATCH("/:id", controllers.UpdateEvent)
+		events.DELETE("/:id", controllers.DeleteEvent)
+
+		events.GET("/:id/budget", controllers.GetEventBudget)
+		events.PUT("/:id/budget", controllers.SetEventBudget)
+		events.GET("/:id/expenses", controllers.GetEventExpenses)
+		events.POST("/:id/expenses", controllers.AddEventExpense)
+		events.DELETE("/:id/expenses/:expense_id", controllers.DeleteEventExpense)
+
+		events.GET("/:id/guests", controllers.GetEventGuests)
+		events.POST("/:id/guests", controllers.AddEventGuest)
+		events.POST("/:id/guests/import", controllers.ImportEventGuests)
+		events.POST("/:id/guests/invite", controllers.SendEventInvites)
+		events.PATCH("/:id/guests/:guest_id", controllers.UpdateEventGuest)
+		events.DELETE("/:id/guests/:guest_id", controllers.DeleteEventGuest)
+		events.GET("/:id/headcount", controllers.GetEventHeadcount)
+
+		events.GET("/:id/checklist", controllers.GetEventChecklist)
+		events.POST("/:id/checklist", controllers.ApplyEventChecklist)
+		events.POST("/:id/tasks", controllers.AddEventTask)
+		events.PATCH("/:id/tasks/:task_id", controllers.UpdateEventTask)
+		events.DELETE("/:id/tasks/:task_id", controllers.DeleteEventTask)
+
+		events.GET("/:id/audit", controllers.GetEventAudit)
+	}
+
+	// RSVP pages are public; the token identifies the guest
+	r.GET("/rsvp/:token", controllers.GetRSVP)
+	r.POST("/rsvp/:token", controllers.RespondRSVP)
+}
+
+func SetupMessageRoutes(r *gin.Engine) {
+	conversations := r.Group("/conversations", middleware.ClerkAuthMiddleware())
+	{
+		conversations.GET("/", controllers.GetMyConversations)
+		conversations.POST("/", controllers.StartConversation)
+		conversations.GET("/unread", controllers.GetUnreadMessageCounts)
+		conversations.GET("/:id", controllers.GetConversation)
+		conversations.POST("/:id/messages", controllers.SendMessage)
+		conversations.POST("/:id/read", controllers.MarkConversationRead)
+	}
+}
+
+func SetupRealtimeRoutes(r *gin.Engine) {
+	r.GET("/realtime/stream", middleware.ClerkAuthMiddleware(), controllers.StreamRealtime)
+}
+
+func SetupPlannerRoutes(r *gin.Engine) {
+	planner := r.Group("/planner", middleware.ClerkAuthMiddleware(), middleware.RequireRoleMiddleware("planner"))
+	{
+		planner.POST("/clients", controllers.InvitePlannerClient)
+		planner.GET("/clients", controllers.GetPlannerClients)
+		planner.DELETE("/clients/:client_id", controllers.RemovePlannerClient)
+		planner.POST("/clients/:client_id/events", controllers.CreateClientEvent)
+		planner.GET("/dashboard", controllers.GetPlannerDashboard)
+		planner.GET("/requests", controllers.GetPlannerSentRequests)
+		planner.POST("/events/:id/bookings", controllers.CreatePlannerBooking)
+		planner.POST("/events/:id/saved-vendors", controllers.CreatePlannerSavedVendor)
+	}
+
+	// the client side: accepting planners and approving what they ask for
+	planners := r.Group("/planners", middleware.ClerkAuthMiddleware())
+	{
+		planners.GET("/", controllers.GetMyPlanners)
+		planners.GET("/audit", controllers.GetMyAudit)
+		planners.GET("/requests", controllers.GetPlannerRequests)
+		planners.POST("/requests/:request_id/approve", controllers.ApprovePlannerRequest)
+		planners.POST("/requests/:request_id/reject", controllers.RejectPlannerRequest)
+		planners.POST("/:planner_id/accept", controllers.AcceptPlanner)
+		planners.PATCH("/:planner_id", controllers.UpdatePlanner)
+		planners.DELETE("/:planner_id", controllers.RemovePlanner)
+	}
+}
+
 func SeTupSendNotification(r *gin.Engine) {
 	notification := r.Group("/notif", middleware.ClerkAuthMiddleware())
 	{
@@ -72,3 +200,40 @@ func SetupVendorDealsRoutes(router *gin.Engine) {
 		vendorDealsRoutes.GET("/", controllers.GetAllVendorsDeals)
 	}
 }
+
+func SetupAdminRoutes(router *gin.Engine) {
+	adminRoutes := router.Group("/admin", middleware.ClerkAuthMiddleware(), middleware.RequireRoleMiddleware("admin"))
+	{
+		adminRoutes.POST("/vendor-imports", controllers.CreateVendorImport)
+		adminRoutes.GET("/vendor-imports/:id", controllers.GetVendorImport)
+		adminRoutes.GET("/vendor-imports/:id/errors", controllers.GetVendorImportErrors)
+
+		adminRoutes.GET("/exports/users", controllers.ExportUsers)
+		adminRoutes.GET("/exports/vendors", controllers.ExportVendors)
+		adminRoutes.GET("/exports/bookings", controllers.ExportBookings)
+
+		adminRoutes.GET("/questions/flagged", controllers.GetFlaggedVendorQuestions)
+		adminRoutes.PATCH("/questions/:id", controllers.ModerateVendorQuestion)
+
+		adminRoutes.GET("/search/synonyms", controllers.GetSearchSynonyms)
+		adminRoutes.POST("/search/synonyms", controllers.UpsertSearchSynonym)
+		adminRoutes.DELETE("/search/synonyms/:id", controllers.DeleteSearchSynonym)
+		adminRoutes.GET("/search/settings", controllers.GetSearchSettings)
+		adminRoutes.PUT("/search/settings", controllers.UpdateSearchSettings)
+		adminRoutes.GET("/search/analytics/top-queries", controllers.GetTopSearchQueries)
+		adminRoutes.GET("/search/analytics/zero-results", controllers.GetZeroResultSearchQueries)
+		adminRoutes.GET("/search/analytics/click-through", controllers.GetSearchClickThrough)
+
+		adminRoutes.GET("/checklist-templates", controllers.GetChecklistTemplates)
+		adminRoutes.POST("/checklist-templates", controllers.CreateChecklistTemplate)
+		adminRoutes.DELETE("/checklist-templates/:id", controllers.DeleteChecklistTemplate)
+	}
+}
+
+func SetupExportRoutes(router *gin.Engine) {
+	exportRoutes := router.Group("/exports", middleware.ClerkAuthMiddleware(), middleware.RequireRoleMiddleware("vendor", "admin"))
+	{
+		exportRoutes.GET("/vendor/bookings", controllers.ExportVendorBookings)
+		exportRoutes.GET("/vendor/deals", controllers.ExportVendorDeals)
+	}
+}
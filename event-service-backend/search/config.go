@@ -0,0 +1,120 @@
+package search
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/lib/pq"
+)
+
+const (
+	// how long search reuses synonyms and settings before re-reading them
+	configTTL = time.Minute
+	// cap on synonym variants OR-ed into one search
+	maxQueryVariants = 8
+)
+
+var defaultSettings = models.SearchSettings{
+	WeightTitle:       1.0,
+	WeightCategory:    0.4,
+	WeightDescription: 0.2,
+	WeightLocation:    0.1,
+	RatingBoost:       0.5,
+	FeaturedBoost:     0.25,
+}
+
+var configCache struct {
+	sync.Mutex
+	settings models.SearchSettings
+	synonyms map[string][]string
+	loadedAt time.Time
+}
+
+// currentConfig returns the ranking settings and synonyms dictionary,
+// re-reading them at most once per configTTL. On a read error the last good
+// copy (or the defaults) is used so search keeps working.
+func currentConfig() (models.SearchSettings, map[string][]string) {
+	configCache.Lock()
+	defer configCache.Unlock()
+
+	if time.Since(configCache.loadedAt) < configTTL {
+		return configCache.settings, configCache.synonyms
+	}
+	if configCache.synonyms == nil {
+		configCache.settings = defaultSettings
+		configCache.synonyms = map[string][]string{}
+	}
+	configCache.loadedAt = time.Now()
+
+	if s, err := LoadSettings(); err != nil {
+		log.Printf("Error loading search settings: %v", err)
+	} else {
+		configCache.settings = s
+	}
+
+	rows, err := config.DB.Query(`SELECT term, synonyms FROM search_synonyms`)
+	if err != nil {
+		log.Printf("Error loading search synonyms: %v", err)
+		return configCache.settings, configCache.synonyms
+	}
+	defer rows.Close()
+
+	synonyms := map[string][]string{}
+	for rows.Next() {
+		var term string
+		var syns pq.StringArray
+		if err := rows.Scan(&term, &syns); err != nil {
+			log.Printf("Error scanning search synonym: %v", err)
+			continue
+		}
+		synonyms[term] = syns
+	}
+	configCache.synonyms = synonyms
+
+	return configCache.settings, configCache.synonyms
+}
+
+// InvalidateConfig makes the next search re-read settings and synonyms.
+func InvalidateConfig() {
+	configCache.Lock()
+	configCache.loadedAt = time.Time{}
+	configCache.Unlock()
+}
+
+// LoadSettings reads the ranking settings row.
+func LoadSettings() (models.SearchSettings, error) {
+	var s models.SearchSettings
+	err := config.DB.QueryRow(`
+		SELECT weight_title, weight_category, weight_description, weight_location,
+		       rating_boost, featured_boost, updated_at
+		FROM search_settings WHERE id = 1
+	`).Scan(&s.WeightTitle, &s.WeightCategory, &s.WeightDescription, &s.WeightLocation,
+		&s.RatingBoost, &s.FeaturedBoost, &s.UpdatedAt)
+	return s, err
+}
+
+// expandQuery returns the query rewritten with each synonym in place of a
+// matching term ("dj in pune" -> "entertainment in pune"). The original
+// query is not included.
+func expandQuery(query string, synonyms map[string][]string) []string {
+	lower := strings.ToLower(query)
+	var variants []string
+	for term, syns := range synonyms {
+		re, err := regexp.Compile(`(^|[^\p{L}\p{N}])` + regexp.QuoteMeta(term) + `($|[^\p{L}\p{N}])`)
+		if err != nil || !re.MatchString(lower) {
+			continue
+		}
+		for _, syn := range syns {
+			variants = append(variants, re.ReplaceAllString(lower, "${1}"+syn+"${2}"))
+			if len(variants) == maxQueryVariants {
+				return variants
+			}
+		}
+	}
+	return variants
+}
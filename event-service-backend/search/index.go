@@ -0,0 +1,131 @@
+// Package search is the vendor search index. Handlers talk to the Index
+// interface; Postgres full-text search is the production implementation and
+// Memory a dependency-free one for tests and local tools.
+package search
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+)
+
+// Vendors is the index used by the app, set up in main.
+var Vendors Index
+
+// Index keeps vendor listings searchable. Index and Delete are called after
+// every write to a listing (or its deals) so implementations that keep their
+// own copy stay in sync.
+type Index interface {
+	Index(ctx context.Context, doc Document) error
+	Delete(ctx context.Context, vendorID int) error
+	// Search returns one page of results with facet counts.
+	Search(ctx context.Context, q Query) (*Result, error)
+	// MatchingIDs returns the ids of every listing matching q, unpaged.
+	MatchingIDs(ctx context.Context, q Query) ([]int, error)
+}
+
+// Document is what gets indexed for one listing.
+type Document struct {
+	models.VendorListing
+	PriceMin *float64 // lower bound parsed from PriceRange
+	HasDeal  bool     // a deal is running right now
+}
+
+// Query is a vendor search. Text is required; every other field is an
+// optional filter. Categories and Cities are lowercased.
+type Query struct {
+	Text     string
+	Lang     string // ISO 639 code; "" means en
+	Location string
+	// From..To (inclusive days) hides listings that are blocked or have a
+	// confirmed booking on any of them. Both or neither are set.
+	From, To *time.Time
+	// NextAvailable fills each result's NextAvailableDate, counting from
+	// From or today.
+	NextAvailable bool
+
+	Categories   []string
+	Cities       []string
+	PriceBuckets []string // keys of PriceBuckets
+	MinRating    *float64
+	HasDeal      bool
+	CreatedAfter *time.Time
+
+	Sort     string // a utils.Sort* key; "" means relevance
+	Lat, Lng *float64
+	Limit    int // 0 means utils.DefaultPageLimit
+	Cursor   string
+}
+
+// Result is one page of search results. Facet counts cover every match, with
+// each facet ignoring its own filter.
+type Result struct {
+	Vendors    []models.VendorListing
+	Total      int
+	Facets     map[string][]models.FacetCount
+	Fuzzy      bool   // matched by similarity because exact matches were scarce
+	DidYouMean string // spelling suggestion, "" when there is none
+	Page       utils.Page
+	NextCursor string
+}
+
+// QueryError is returned for queries that are invalid rather than failed.
+type QueryError struct {
+	Msg string
+}
+
+func (e *QueryError) Error() string { return e.Msg }
+
+// PriceBuckets drive both the "price" facet and the price filter. Listings
+// are bucketed by the lower bound of their price range; Max 0 means no cap.
+var PriceBuckets = []struct {
+	Key      string
+	Min, Max float64
+}{
+	{"under_10k", 0, 10000},
+	{"10k_50k", 10000, 50000},
+	{"50k_2l", 50000, 200000},
+	{"over_2l", 200000, 0},
+}
+
+// ratingBuckets are cumulative ("4 and up"), so a 4.6 vendor counts in all three
+var ratingBuckets = []float64{4.5, 4, 3}
+
+// ValidPriceBucket reports whether key names one of PriceBuckets.
+func ValidPriceBucket(key string) bool {
+	for _, b := range PriceBuckets {
+		if b.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func inPriceBucket(key string, price float64) bool {
+	for _, b := range PriceBuckets {
+		if b.Key == key {
+			return price >= b.Min && (b.Max == 0 || price < b.Max)
+		}
+	}
+	return false
+}
+
+var queryWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+func emptyFacets() map[string][]models.FacetCount {
+	return map[string][]models.FacetCount{
+		"category": {},
+		"city":     {},
+		"price":    {},
+		"rating":   {},
+		"has_deal": {},
+	}
+}
+
+var (
+	_ Index = (*Postgres)(nil)
+	_ Index = (*Memory)(nil)
+)
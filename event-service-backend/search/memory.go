@@ -0,0 +1,319 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+)
+
+// Memory is an Index that keeps listings in a map and matches by plain word
+// containment. It needs no database, which makes it the index for tests.
+// Stemming, synonyms and fuzzy matching are not supported, and queries
+// using availability (From, To, NextAvailable) are rejected rather than
+// answered differently from Postgres.
+type Memory struct {
+	mu   sync.RWMutex
+	docs map[int]Document
+}
+
+func NewMemory() *Memory {
+	return &Memory{docs: map[int]Document{}}
+}
+
+func (m *Memory) Index(ctx context.Context, doc Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.docs[doc.ID] = doc
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, vendorID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.docs, vendorID)
+	return nil
+}
+
+// memoryHit is a matched document with its relevance score
+type memoryHit struct {
+	doc   Document
+	score float64
+}
+
+// match returns the documents matching the text and base filters.
+func (m *Memory) match(q Query) ([]memoryHit, error) {
+	words := queryWord.FindAllString(strings.ToLower(q.Text), -1)
+	if len(words) == 0 {
+		return nil, &QueryError{"Search query is required"}
+	}
+	if q.From != nil || q.To != nil || q.NextAvailable {
+		return nil, &QueryError{"availability needs the Postgres index"}
+	}
+	for _, key := range q.PriceBuckets {
+		if !ValidPriceBucket(key) {
+			return nil, &QueryError{"Unknown price bucket: " + key}
+		}
+	}
+	location := strings.ToLower(strings.TrimSpace(q.Location))
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var hits []memoryHit
+	for _, doc := range m.docs {
+		if location != "" && !strings.Contains(strings.ToLower(doc.Location), location) {
+			continue
+		}
+		if q.CreatedAfter != nil && !doc.CreatedAt.After(*q.CreatedAfter) {
+			continue
+		}
+		score, ok := memoryScore(doc, words)
+		if !ok {
+			continue
+		}
+		hits = append(hits, memoryHit{doc, score})
+	}
+	return hits, nil
+}
+
+// memoryScore mirrors the Postgres ranking with the default weights: every
+// word must appear somewhere, and appearances in the title count most.
+func memoryScore(doc Document, words []string) (float64, bool) {
+	fields := []struct {
+		text   string
+		weight float64
+	}{
+		{strings.ToLower(doc.Title), defaultSettings.WeightTitle},
+		{strings.ToLower(doc.Category), defaultSettings.WeightCategory},
+		{strings.ToLower(doc.Description), defaultSettings.WeightDescription},
+		{strings.ToLower(doc.Location), defaultSettings.WeightLocation},
+	}
+
+	score := 0.0
+	for _, w := range words {
+		found := false
+		for _, f := range fields {
+			if strings.Contains(f.text, w) {
+				score += f.weight
+				found = true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+
+	score *= 1 + defaultSettings.RatingBoost*doc.Rating/5
+	if doc.Featured {
+		score *= 1 + defaultSettings.FeaturedBoost
+	}
+	return score, true
+}
+
+// passes reports whether doc satisfies every facet filter except skipFacet.
+func (q Query) passes(doc Document, skipFacet string) bool {
+	if skipFacet != "category" && len(q.Categories) > 0 && !contains(q.Categories, strings.ToLower(doc.Category)) {
+		return false
+	}
+	if skipFacet != "city" && len(q.Cities) > 0 && !contains(q.Cities, strings.ToLower(doc.City)) {
+		return false
+	}
+	if skipFacet != "price" && len(q.PriceBuckets) > 0 {
+		if doc.PriceMin == nil {
+			return false
+		}
+		inAny := false
+		for _, key := range q.PriceBuckets {
+			inAny = inAny || inPriceBucket(key, *doc.PriceMin)
+		}
+		if !inAny {
+			return false
+		}
+	}
+	if skipFacet != "rating" && q.MinRating != nil && doc.Rating < *q.MinRating {
+		return false
+	}
+	if skipFacet != "has_deal" && q.HasDeal && !doc.HasDeal {
+		return false
+	}
+	return true
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Memory) Search(ctx context.Context, q Query) (*Result, error) {
+	hits, err := m.match(q)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the direction of each key matters here; values are computed below
+	sorts := map[string]utils.SortKey{
+		utils.SortRelevance: {Desc: true},
+		utils.SortNewest:    {Desc: true},
+		utils.SortRating:    {Desc: true},
+		utils.SortPrice:     {},
+		utils.SortDistance:  {},
+	}
+	page, err := utils.NewPage(q.Limit, q.Sort, q.Cursor, sorts, utils.SortRelevance, "")
+	if err != nil {
+		return nil, &QueryError{err.Error()}
+	}
+	if page.Sort == utils.SortDistance && (q.Lat == nil || q.Lng == nil) {
+		return nil, &QueryError{utils.ErrDistanceNeedsPosition.Error()}
+	}
+
+	type ranked struct {
+		doc   Document
+		value float64
+	}
+	var results []ranked
+	for _, h := range hits {
+		if q.passes(h.doc, "") {
+			results = append(results, ranked{h.doc, sortValue(page.Sort, h, q)})
+		}
+	}
+
+	desc := page.Desc()
+	less := func(a, b ranked) bool {
+		if a.value != b.value {
+			return a.value < b.value != desc
+		}
+		return a.doc.ID != b.doc.ID && a.doc.ID < b.doc.ID != desc
+	}
+	sort.Slice(results, func(i, j int) bool { return less(results[i], results[j]) })
+
+	res := &Result{Vendors: []models.VendorListing{}, Total: len(results), Facets: m.facets(hits, q)}
+
+	start := 0
+	if raw, id, ok := page.After(); ok {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, &QueryError{"invalid cursor"}
+		}
+		after := ranked{Document{VendorListing: models.VendorListing{ID: id}}, value}
+		start = sort.Search(len(results), func(i int) bool { return less(after, results[i]) })
+	}
+	for _, r := range results[start:min(len(results), start+page.FetchLimit())] {
+		page.Track(strconv.FormatFloat(r.value, 'g', -1, 64), r.doc.ID)
+		res.Vendors = append(res.Vendors, r.doc.VendorListing)
+	}
+
+	n, next := page.Done()
+	res.Vendors = res.Vendors[:n]
+	res.Page, res.NextCursor = page, next
+	return res, nil
+}
+
+func sortValue(key string, h memoryHit, q Query) float64 {
+	switch key {
+	case utils.SortNewest:
+		return float64(h.doc.CreatedAt.UnixMicro())
+	case utils.SortRating:
+		return h.doc.Rating
+	case utils.SortPrice:
+		if h.doc.PriceMin == nil {
+			return 1e15
+		}
+		return *h.doc.PriceMin
+	case utils.SortDistance:
+		if h.doc.Latitude == nil || h.doc.Longitude == nil {
+			return 1e9
+		}
+		return distanceKm(*q.Lat, *q.Lng, *h.doc.Latitude, *h.doc.Longitude)
+	default:
+		return h.score
+	}
+}
+
+// distanceKm is the great-circle distance, like the distance_km SQL function.
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func (m *Memory) facets(hits []memoryHit, q Query) map[string][]models.FacetCount {
+	counts := map[string]map[string]int{}
+	count := func(facet, value string) {
+		if counts[facet] == nil {
+			counts[facet] = map[string]int{}
+		}
+		counts[facet][value]++
+	}
+
+	for _, h := range hits {
+		d := h.doc
+		if q.passes(d, "category") {
+			count("category", d.Category)
+		}
+		if d.City != "" && q.passes(d, "city") {
+			count("city", d.City)
+		}
+		if d.PriceMin != nil && q.passes(d, "price") {
+			for _, b := range PriceBuckets {
+				if inPriceBucket(b.Key, *d.PriceMin) {
+					count("price", b.Key)
+				}
+			}
+		}
+		if q.passes(d, "rating") {
+			for _, r := range ratingBuckets {
+				if d.Rating >= r {
+					count("rating", fmt.Sprintf("%g", r))
+				}
+			}
+		}
+		if q.passes(d, "has_deal") {
+			count("has_deal", strconv.FormatBool(d.HasDeal))
+		}
+	}
+
+	facets := emptyFacets()
+	for facet, values := range counts {
+		for v, n := range values {
+			facets[facet] = append(facets[facet], models.FacetCount{Value: v, Count: n})
+		}
+		sort.Slice(facets[facet], func(i, j int) bool {
+			a, b := facets[facet][i], facets[facet][j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Value < b.Value
+		})
+	}
+	return facets
+}
+
+func (m *Memory) MatchingIDs(ctx context.Context, q Query) ([]int, error) {
+	hits, err := m.match(q)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, h := range hits {
+		if q.passes(h.doc, "") {
+			ids = append(ids, h.doc.ID)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
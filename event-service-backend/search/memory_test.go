@@ -0,0 +1,197 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// testIndex holds five photographers and one caterer.
+func testIndex(t *testing.T) *Memory {
+	t.Helper()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	docs := []Document{
+		{VendorListing: models.VendorListing{ID: 1, Title: "Lens Studio", Category: "photography", City: "Pune",
+			Rating: 4.8, Latitude: ptr(18.52), Longitude: ptr(73.85), CreatedAt: base}, PriceMin: ptr(8000.0)},
+		{VendorListing: models.VendorListing{ID: 2, Title: "Candid Photography", Category: "photography", City: "Mumbai",
+			Rating: 4.2, Latitude: ptr(19.07), Longitude: ptr(72.87), CreatedAt: base.Add(time.Hour)}, PriceMin: ptr(25000.0), HasDeal: true},
+		{VendorListing: models.VendorListing{ID: 3, Title: "Wedding Photography Co", Category: "photography", City: "Pune",
+			Rating: 3.5, CreatedAt: base.Add(2 * time.Hour)}, PriceMin: ptr(60000.0)},
+		{VendorListing: models.VendorListing{ID: 4, Title: "Frames", Description: "photography for small events",
+			Category: "photography", City: "Delhi", Rating: 4.6, CreatedAt: base.Add(3 * time.Hour)}},
+		{VendorListing: models.VendorListing{ID: 5, Title: "Pixel Photography", Category: "photography", City: "Mumbai",
+			Rating: 4.0, Featured: true, CreatedAt: base.Add(4 * time.Hour)}, PriceMin: ptr(12000.0)},
+		{VendorListing: models.VendorListing{ID: 6, Title: "Spice Route", Category: "catering", City: "Pune",
+			Rating: 4.9, CreatedAt: base.Add(5 * time.Hour)}, PriceMin: ptr(300000.0)},
+	}
+	m := NewMemory()
+	for _, d := range docs {
+		if err := m.Index(context.Background(), d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+func resultIDs(res *Result) []int {
+	ids := []int{}
+	for _, v := range res.Vendors {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func facetCount(res *Result, facet, value string) int {
+	for _, fc := range res.Facets[facet] {
+		if fc.Value == value {
+			return fc.Count
+		}
+	}
+	return 0
+}
+
+func TestMemoryFilters(t *testing.T) {
+	m := testIndex(t)
+	tests := []struct {
+		name string
+		q    Query
+		want []int
+	}{
+		{"text only", Query{Text: "photography"}, []int{1, 2, 3, 4, 5}},
+		{"city", Query{Text: "photography", Cities: []string{"pune"}}, []int{1, 3}},
+		{"price bucket", Query{Text: "photography", PriceBuckets: []string{"10k_50k"}}, []int{2, 5}},
+		{"min rating", Query{Text: "photography", MinRating: ptr(4.5)}, []int{1, 4}},
+		{"has deal", Query{Text: "photography", HasDeal: true}, []int{2}},
+		{"no match", Query{Text: "florist"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := m.MatchingIDs(context.Background(), tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryRejectsInvalidQueries(t *testing.T) {
+	m := testIndex(t)
+	now := time.Now()
+	for name, q := range map[string]Query{
+		"empty text":     {Text: "  "},
+		"price bucket":   {Text: "photography", PriceBuckets: []string{"cheap"}},
+		"availability":   {Text: "photography", From: &now, To: &now},
+		"next available": {Text: "photography", NextAvailable: true},
+		"distance":       {Text: "photography", Sort: utils.SortDistance},
+	} {
+		_, err := m.Search(context.Background(), q)
+		var qe *QueryError
+		if !errors.As(err, &qe) {
+			t.Errorf("%s: got %v, want a QueryError", name, err)
+		}
+	}
+}
+
+func TestMemoryFacetsIgnoreTheirOwnFilter(t *testing.T) {
+	m := testIndex(t)
+	res, err := m.Search(context.Background(), Query{Text: "photography", Cities: []string{"pune"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 2 {
+		t.Errorf("total = %d, want 2", res.Total)
+	}
+	// the city facet still offers the other cities
+	if n := facetCount(res, "city", "Mumbai"); n != 2 {
+		t.Errorf("city Mumbai = %d, want 2", n)
+	}
+	// other facets only count Pune listings
+	if n := facetCount(res, "price", "under_10k"); n != 1 {
+		t.Errorf("price under_10k = %d, want 1", n)
+	}
+	// rating buckets are cumulative
+	if n := facetCount(res, "rating", "3"); n != 2 {
+		t.Errorf("rating 3 = %d, want 2", n)
+	}
+	if n := facetCount(res, "rating", "4.5"); n != 1 {
+		t.Errorf("rating 4.5 = %d, want 1", n)
+	}
+}
+
+func TestMemorySort(t *testing.T) {
+	m := testIndex(t)
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{utils.SortNewest, []int{5, 4, 3, 2, 1}},
+		{utils.SortRating, []int{1, 4, 2, 5, 3}},
+		// listings without a price sort last
+		{utils.SortPrice, []int{1, 5, 2, 3, 4}},
+		// listings without coordinates sort last, by id
+		{utils.SortDistance, []int{1, 2, 3, 4, 5}},
+		// title matches first, boosted by rating and featured
+		{utils.SortRelevance, []int{5, 2, 3, 4, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			res, err := m.Search(context.Background(), Query{Text: "photography", Sort: tt.sort, Lat: ptr(18.52), Lng: ptr(73.85)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := resultIDs(res); !slices.Equal(ids, tt.want) {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryCursorPaging(t *testing.T) {
+	m := testIndex(t)
+	q := Query{Text: "photography", Sort: utils.SortRating, Limit: 2}
+	var got []int
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("paging did not end")
+		}
+		res, err := m.Search(context.Background(), q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Total != 5 {
+			t.Errorf("page %d: total = %d, want 5", page, res.Total)
+		}
+		got = append(got, resultIDs(res)...)
+		if res.NextCursor == "" {
+			break
+		}
+		q.Cursor = res.NextCursor
+	}
+	if want := []int{1, 4, 2, 5, 3}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMemoryDelete(t *testing.T) {
+	m := testIndex(t)
+	if err := m.Delete(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	ids, err := m.MatchingIDs(context.Background(), Query{Text: "photography", Cities: []string{"pune"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ids, []int{3}) {
+		t.Errorf("got %v, want [3]", ids)
+	}
+}
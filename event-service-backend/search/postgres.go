@@ -0,0 +1,430 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dharmaseervi/event-service-backend/models"
+	"github.com/dharmaseervi/event-service-backend/utils"
+	"github.com/lib/pq"
+)
+
+const (
+	// below this many full-text hits the search also matches by trigram similarity
+	fuzzyFallbackBelow = 3
+	// word_similarity needed for a fuzzy hit (0..1)
+	fuzzyMinSimilarity = 0.4
+)
+
+// Postgres searches the vendors table directly with full-text search. The
+// search_vector trigger keeps it up to date, so Index and Delete have
+// nothing to do.
+type Postgres struct {
+	db *sql.DB
+}
+
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (p *Postgres) Index(ctx context.Context, doc Document) error { return nil }
+
+func (p *Postgres) Delete(ctx context.Context, vendorID int) error { return nil }
+
+// facetFilter is a filter the client picked from the facet sidebar. Cond is
+// written against the columns of the "matched" CTE.
+type facetFilter struct {
+	Facet string
+	Cond  string
+}
+
+// pgQuery is a Query compiled to SQL: the "matched" CTE holding every
+// listing that satisfies the text and base filters, the facet filters to
+// apply on top of it, and the arguments bound so far.
+type pgQuery struct {
+	matched string
+	filters []facetFilter
+	args    utils.SQLArgs
+	fuzzy   bool
+}
+
+func (p *Postgres) build(ctx context.Context, q Query) (*pgQuery, error) {
+	text := strings.TrimSpace(q.Text)
+	if text == "" {
+		return nil, &QueryError{"Search query is required"}
+	}
+	lang := q.Lang
+	if lang == "" {
+		lang = "en"
+	}
+
+	built := &pgQuery{args: utils.SQLArgs{text}}
+	arg := built.args.Add
+
+	// The query is parsed with the config for the language and also unstemmed
+	// ('simple'), which the search vector always carries, so Hindi text and
+	// transliterations match listings in any language.
+	langConfig := "vendor_search_config(" + arg(lang) + ")"
+	toTSQuery := func(placeholder string) string {
+		return fmt.Sprintf("websearch_to_tsquery(%s, %s) || websearch_to_tsquery('simple', %s)",
+			langConfig, placeholder, placeholder)
+	}
+
+	// Synonyms ("dj" -> "entertainment") are OR-ed in as rewritten queries
+	settings, synonyms := currentConfig()
+	tsq := toTSQuery("$1")
+	for _, variant := range expandQuery(text, synonyms) {
+		tsq += " || " + toTSQuery(arg(variant))
+	}
+	tsq = "(" + tsq + ")"
+
+	// Fall back to trigram matching when full-text finds (almost) nothing,
+	// e.g. "photgrapher". The decision only looks at the query text, so every
+	// page of the same search uses the same mode.
+	var ftsHits int
+	countArgs := append(utils.SQLArgs{}, built.args...)
+	if err := p.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT 1 FROM vendors WHERE search_vector @@ `+tsq+` LIMIT `+countArgs.Add(fuzzyFallbackBelow)+`
+		) t
+	`, countArgs...).Scan(&ftsHits); err != nil {
+		log.Printf("Error counting full-text hits: %v", err)
+	} else {
+		built.fuzzy = ftsHits < fuzzyFallbackBelow
+	}
+
+	// ts_rank takes weights in {D, C, B, A} order; see the search vector trigger
+	weights := arg(pq.Float64Array{settings.WeightLocation, settings.WeightDescription, settings.WeightCategory, settings.WeightTitle})
+	textMatch := "v.search_vector @@ " + tsq
+	rank := fmt.Sprintf(`ts_rank(%s::float4[], v.search_vector, %s)
+		* (1 + %s::float8 * COALESCE(v.rating, 0)::float8 / 5)
+		* (CASE WHEN v.featured THEN 1 + %s::float8 ELSE 1 END)`,
+		weights, tsq, arg(settings.RatingBoost), arg(settings.FeaturedBoost))
+	if built.fuzzy {
+		similarity := `GREATEST(word_similarity($1, v.title), word_similarity($1, COALESCE(v.category, '')),
+			word_similarity($1, COALESCE(v.description, '')))`
+		textMatch = fmt.Sprintf("(%s OR %s >= %g)", textMatch, similarity, fuzzyMinSimilarity)
+		rank += " + " + similarity
+	}
+
+	matched := `
+	WITH matched AS (
+		SELECT
+			v.id, v.vendor_id, v.title, COALESCE(v.slug, '') AS slug, v.description, v.category,
+			v.price_range, v.location, v.photos, COALESCE(v.rating, 0)::float8 AS rating,
+			COALESCE(v.featured, false) AS featured, v.created_at, v.updated_at,
+			COALESCE(v.city, '') AS city, v.price_min, v.latitude, v.longitude,
+			EXISTS (
+				SELECT 1 FROM vendor_deals d
+				WHERE d.vendor_id = v.id AND NOW() BETWEEN d.start_date AND d.end_date
+			) AS has_deal,
+			` + rank + ` AS rank
+		FROM vendors v
+		WHERE ` + textMatch + `
+	`
+
+	if location := strings.TrimSpace(q.Location); location != "" {
+		matched += " AND v.location ILIKE " + arg("%"+location+"%")
+	}
+
+	if q.From != nil && q.To != nil {
+		matched += fmt.Sprintf(" AND vendor_is_available(v.id, %s::date, %s::date)",
+			arg(q.From.Format(time.DateOnly)), arg(q.To.Format(time.DateOnly)))
+	}
+
+	if q.CreatedAfter != nil {
+		matched += " AND v.created_at > " + arg(*q.CreatedAfter)
+	}
+	built.matched = matched + ")"
+
+	if len(q.Categories) > 0 {
+		built.filters = append(built.filters, facetFilter{"category", "lower(category) = ANY(" + arg(pq.StringArray(q.Categories)) + ")"})
+	}
+	if len(q.Cities) > 0 {
+		built.filters = append(built.filters, facetFilter{"city", "lower(city) = ANY(" + arg(pq.StringArray(q.Cities)) + ")"})
+	}
+	if len(q.PriceBuckets) > 0 {
+		var conds []string
+		for _, key := range q.PriceBuckets {
+			cond, ok := priceBucketCond(key)
+			if !ok {
+				return nil, &QueryError{"Unknown price bucket: " + key}
+			}
+			conds = append(conds, cond)
+		}
+		built.filters = append(built.filters, facetFilter{"price", "(" + strings.Join(conds, " OR ") + ")"})
+	}
+	if q.MinRating != nil {
+		built.filters = append(built.filters, facetFilter{"rating", "rating >= " + arg(*q.MinRating)})
+	}
+	if q.HasDeal {
+		built.filters = append(built.filters, facetFilter{"has_deal", "has_deal"})
+	}
+
+	return built, nil
+}
+
+// sorts returns the sort keys over the matched CTE: the vendor list keys
+// plus relevance.
+func (p *Postgres) sorts(q Query, args *utils.SQLArgs) (map[string]utils.SortKey, error) {
+	sorts, err := utils.VendorSorts("", q.Sort, q.Lat, q.Lng, args)
+	if err != nil {
+		return nil, &QueryError{err.Error()}
+	}
+	sorts[utils.SortRelevance] = utils.SortKey{Expr: "rank::float8", Desc: true, Cast: "float8"}
+	return sorts, nil
+}
+
+func (p *Postgres) Search(ctx context.Context, q Query) (*Result, error) {
+	built, err := p.build(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	args := built.args
+	arg := args.Add
+
+	// the facet query shares the CTE but not the sort/cursor arguments below
+	facetArgs := len(args)
+
+	sorts, err := p.sorts(q, &args)
+	if err != nil {
+		return nil, err
+	}
+	page, err := utils.NewPage(q.Limit, q.Sort, q.Cursor, sorts, utils.SortRelevance, "id")
+	if err != nil {
+		return nil, &QueryError{err.Error()}
+	}
+
+	res := &Result{Vendors: []models.VendorListing{}, Fuzzy: built.fuzzy}
+
+	nextAvailable := "''"
+	if q.NextAvailable {
+		from := "CURRENT_DATE"
+		if q.From != nil {
+			from = arg(q.From.Format(time.DateOnly)) + "::date"
+		}
+		nextAvailable = "COALESCE(vendor_next_available_date(id, " + from + ")::text, '')"
+	}
+
+	// total is taken before the cursor condition so every page reports the full count
+	query := built.matched + `
+	SELECT id, vendor_id, title, slug, description, category, price_range, location, photos,
+	       rating, featured, city, latitude, longitude, created_at, updated_at, total,
+	       ` + nextAvailable + `, ` + page.SortValue() + `
+	FROM (
+		SELECT id, vendor_id, title, slug, description, category, price_range, location, photos,
+		       rating, featured, city, latitude, longitude, created_at, updated_at, rank, price_min,
+		       COUNT(*) OVER () AS total
+		FROM matched
+		WHERE ` + facetWhere(built.filters, "") + `
+	) results
+	WHERE ` + page.Where(arg) + `
+	ORDER BY ` + page.OrderBy() + `
+	LIMIT ` + arg(page.FetchLimit())
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var vendor models.VendorListing
+		var sortValue string
+
+		err := rows.Scan(
+			&vendor.ID,
+			&vendor.VendorID,
+			&vendor.Title,
+			&vendor.Slug,
+			&vendor.Description,
+			&vendor.Category,
+			&vendor.PriceRange,
+			&vendor.Location,
+			&vendor.Photos,
+			&vendor.Rating,
+			&vendor.Featured,
+			&vendor.City,
+			&vendor.Latitude,
+			&vendor.Longitude,
+			&vendor.CreatedAt,
+			&vendor.UpdatedAt,
+			&res.Total,
+			&vendor.NextAvailableDate,
+			&sortValue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		page.Track(sortValue, vendor.ID)
+		res.Vendors = append(res.Vendors, vendor)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res.Facets, err = p.facets(ctx, built.matched, args[:facetArgs], built.filters)
+	if err != nil {
+		return nil, fmt.Errorf("computing facets: %w", err)
+	}
+
+	if built.fuzzy {
+		res.DidYouMean = p.didYouMean(ctx, q.Text)
+	}
+
+	n, next := page.Done()
+	res.Vendors = res.Vendors[:n]
+	res.Page, res.NextCursor = page, next
+	return res, nil
+}
+
+func (p *Postgres) MatchingIDs(ctx context.Context, q Query) ([]int, error) {
+	built, err := p.build(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, built.matched+`
+	SELECT id FROM matched WHERE `+facetWhere(built.filters, "")+` ORDER BY id
+	`, built.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// facets counts every facet over the matched set in one round trip.
+func (p *Postgres) facets(ctx context.Context, matched string, args []any, filters []facetFilter) (map[string][]models.FacetCount, error) {
+	var priceCase strings.Builder
+	priceCase.WriteString("CASE")
+	for _, b := range PriceBuckets {
+		cond, _ := priceBucketCond(b.Key)
+		priceCase.WriteString(fmt.Sprintf(" WHEN %s THEN '%s'", cond, b.Key))
+	}
+	priceCase.WriteString(" END")
+
+	var ratingValues []string
+	for _, r := range ratingBuckets {
+		ratingValues = append(ratingValues, fmt.Sprintf("('%g', %g::float8)", r, r))
+	}
+
+	query := matched + `
+	SELECT 'category', COALESCE(category, ''), COUNT(*) FROM matched
+	WHERE ` + facetWhere(filters, "category") + ` GROUP BY 2
+	UNION ALL
+	SELECT 'city', city, COUNT(*) FROM matched
+	WHERE city <> '' AND ` + facetWhere(filters, "city") + ` GROUP BY 2
+	UNION ALL
+	SELECT 'price', ` + priceCase.String() + `, COUNT(*) FROM matched
+	WHERE price_min IS NOT NULL AND ` + facetWhere(filters, "price") + ` GROUP BY 2
+	UNION ALL
+	SELECT 'rating', b.label, COUNT(*) FROM matched
+	JOIN (VALUES ` + strings.Join(ratingValues, ", ") + `) AS b(label, min_rating) ON matched.rating >= b.min_rating
+	WHERE ` + facetWhere(filters, "rating") + ` GROUP BY 2
+	UNION ALL
+	SELECT 'has_deal', has_deal::text, COUNT(*) FROM matched
+	WHERE ` + facetWhere(filters, "has_deal") + ` GROUP BY 2
+	ORDER BY 1, 3 DESC`
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := emptyFacets()
+	for rows.Next() {
+		var facet string
+		var fc models.FacetCount
+		if err := rows.Scan(&facet, &fc.Value, &fc.Count); err != nil {
+			return nil, err
+		}
+		facets[facet] = append(facets[facet], fc)
+	}
+	return facets, rows.Err()
+}
+
+// didYouMean rewrites each word of the query to the closest word in the
+// listing vocabulary, or returns "" when there is nothing better to offer.
+func (p *Postgres) didYouMean(ctx context.Context, query string) string {
+	words := queryWord.FindAllString(strings.ToLower(query), 8)
+	if len(words) == 0 {
+		return ""
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT t.w,
+		       EXISTS (SELECT 1 FROM search_vocabulary sv WHERE sv.word = t.w),
+		       (SELECT sv.word FROM search_vocabulary sv
+		        WHERE sv.word % t.w
+		        ORDER BY similarity(sv.word, t.w) DESC, sv.ndoc DESC
+		        LIMIT 1)
+		FROM unnest($1::text[]) WITH ORDINALITY AS t(w, i)
+		ORDER BY t.i
+	`, pq.StringArray(words))
+	if err != nil {
+		log.Printf("didYouMean query error: %v", err)
+		return ""
+	}
+	defer rows.Close()
+
+	changed := false
+	var out []string
+	for rows.Next() {
+		var word string
+		var known bool
+		var closest *string
+		if err := rows.Scan(&word, &known, &closest); err != nil {
+			log.Printf("didYouMean scan error: %v", err)
+			return ""
+		}
+		if !known && closest != nil {
+			word = *closest
+			changed = true
+		}
+		out = append(out, word)
+	}
+	if !changed {
+		return ""
+	}
+	return strings.Join(out, " ")
+}
+
+// facetWhere ANDs together every filter except the one for skipFacet.
+func facetWhere(filters []facetFilter, skipFacet string) string {
+	conds := []string{"TRUE"}
+	for _, f := range filters {
+		if f.Facet != skipFacet {
+			conds = append(conds, f.Cond)
+		}
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func priceBucketCond(key string) (string, bool) {
+	for _, b := range PriceBuckets {
+		if b.Key != key {
+			continue
+		}
+		cond := fmt.Sprintf("price_min >= %g", b.Min)
+		if b.Max > 0 {
+			cond += fmt.Sprintf(" AND price_min < %g", b.Max)
+		}
+		return cond, true
+	}
+	return "", false
+}
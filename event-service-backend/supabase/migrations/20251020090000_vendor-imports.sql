@@ -0,0 +1,32 @@
+-- ================================
+-- Bulk vendor imports
+-- ================================
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS external_ref TEXT;
+
+-- Upserts match on the partner's own reference, scoped to the owning vendor
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vendors_external_ref
+  ON vendors(vendor_id, external_ref) WHERE external_ref IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS vendor_import_jobs (
+  id SERIAL PRIMARY KEY,
+  created_by INT REFERENCES users(id) ON DELETE SET NULL,
+  format TEXT NOT NULL CHECK (format IN ('csv','jsonl')),
+  dry_run BOOLEAN DEFAULT FALSE,
+  upsert BOOLEAN DEFAULT FALSE,
+  status TEXT DEFAULT 'pending' CHECK (status IN ('pending','running','completed','failed')),
+  total_rows INT DEFAULT 0,
+  processed_rows INT DEFAULT 0,
+  created_rows INT DEFAULT 0,
+  updated_rows INT DEFAULT 0,
+  failed_rows INT DEFAULT 0,
+  errors JSONB DEFAULT '[]'::jsonb,   -- per-row error report
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(), -- last progress save; stale running jobs are failed
+  finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_import_jobs_created_by ON vendor_import_jobs(created_by);
+
+-- Finds unfinished jobs whose runner went away
+CREATE INDEX IF NOT EXISTS idx_vendor_import_jobs_unfinished
+  ON vendor_import_jobs(updated_at) WHERE status IN ('pending','running');
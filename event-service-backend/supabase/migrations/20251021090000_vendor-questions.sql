@@ -0,0 +1,33 @@
+-- ================================
+-- Vendor Q&A
+-- ================================
+CREATE TABLE IF NOT EXISTS vendor_questions (
+  id SERIAL PRIMARY KEY,
+  vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE SET NULL,
+  question TEXT NOT NULL,
+  answer TEXT,
+  answered_at TIMESTAMPTZ,
+  upvotes INT DEFAULT 0,
+  flag_count INT DEFAULT 0,
+  hidden BOOLEAN DEFAULT FALSE,      -- set by admins or automatically after enough flags
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_questions_vendor_id ON vendor_questions(vendor_id);
+
+CREATE TABLE IF NOT EXISTS vendor_question_votes (
+  question_id INT REFERENCES vendor_questions(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (question_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS vendor_question_flags (
+  question_id INT REFERENCES vendor_questions(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE CASCADE,
+  reason TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (question_id, user_id)
+);
@@ -0,0 +1,54 @@
+-- ================================
+-- Vendor slugs
+-- ================================
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS slug TEXT;
+
+-- Backfill existing listings with the slug the API would give them
+-- (vendorSlugBase: title and city, the last comma-separated part of the
+-- location), so their first edit does not rename them. Clashes get -2, -3...
+-- in id order, as in uniqueVendorSlug.
+DO $$
+DECLARE
+  v RECORD;
+  city TEXT;
+  base TEXT;
+  candidate TEXT;
+  n INT;
+BEGIN
+  FOR v IN SELECT id, title, location FROM vendors WHERE slug IS NULL ORDER BY id LOOP
+    SELECT trim(p) INTO city
+    FROM unnest(string_to_array(COALESCE(v.location, ''), ',')) WITH ORDINALITY AS t(p, i)
+    WHERE trim(p) <> ''
+    ORDER BY i DESC
+    LIMIT 1;
+
+    base := concat_ws('-',
+      NULLIF(trim(BOTH '-' FROM regexp_replace(lower(COALESCE(v.title, '')), '[^a-z0-9]+', '-', 'g')), ''),
+      NULLIF(trim(BOTH '-' FROM regexp_replace(lower(COALESCE(city, '')), '[^a-z0-9]+', '-', 'g')), ''));
+    base := rtrim(left(base, 80), '-');
+    IF base = '' THEN
+      base := 'vendor';
+    ELSIF base ~ '^[0-9]+$' THEN
+      base := 'vendor-' || base;
+    END IF;
+
+    candidate := base;
+    n := 1;
+    WHILE EXISTS (SELECT 1 FROM vendors WHERE slug = candidate) LOOP
+      n := n + 1;
+      candidate := base || '-' || n;
+    END LOOP;
+    UPDATE vendors SET slug = candidate WHERE id = v.id;
+  END LOOP;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vendors_slug ON vendors(slug);
+
+-- Old slugs keep resolving (with a 301) after a listing is renamed
+CREATE TABLE IF NOT EXISTS vendor_slug_history (
+  slug TEXT PRIMARY KEY,
+  vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_slug_history_vendor_id ON vendor_slug_history(vendor_id);
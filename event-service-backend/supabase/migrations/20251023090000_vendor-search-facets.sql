@@ -0,0 +1,17 @@
+-- ================================
+-- Search facets
+-- ================================
+-- City is the last comma-separated part of the location
+-- ("Koramangala, Bangalore" -> "Bangalore"), matching the slug logic.
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS city TEXT
+  GENERATED ALWAYS AS (NULLIF(trim(regexp_replace(COALESCE(location, ''), '^.*,', '')), '')) STORED;
+
+-- Numeric bounds parsed from the free-text price_range ("₹10,000 - ₹50,000")
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS price_min NUMERIC
+  GENERATED ALWAYS AS (substring(replace(price_range, ',', '') from '[0-9]+(?:\.[0-9]+)?')::numeric) STORED;
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS price_max NUMERIC
+  GENERATED ALWAYS AS (substring(replace(price_range, ',', '') from '([0-9]+(?:\.[0-9]+)?)[^0-9]*$')::numeric) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_vendors_city ON vendors(lower(city));
+CREATE INDEX IF NOT EXISTS idx_vendors_price_min ON vendors(price_min);
+CREATE INDEX IF NOT EXISTS idx_vendor_deals_vendor_id ON vendor_deals(vendor_id, start_date, end_date);
@@ -0,0 +1,20 @@
+-- ================================
+-- Vendor coordinates (distance sort)
+-- ================================
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+-- Great-circle distance in km (haversine); NULL when either point is unknown
+CREATE OR REPLACE FUNCTION distance_km(lat1 DOUBLE PRECISION, lng1 DOUBLE PRECISION,
+                                       lat2 DOUBLE PRECISION, lng2 DOUBLE PRECISION)
+RETURNS DOUBLE PRECISION
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT 6371 * 2 * asin(sqrt(
+    power(sin(radians(lat2 - lat1) / 2), 2) +
+    cos(radians(lat1)) * cos(radians(lat2)) * power(sin(radians(lng2 - lng1) / 2), 2)
+  ))
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_vendors_created_at ON vendors(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_bookings_user_created ON bookings(user_id, created_at DESC, id DESC);
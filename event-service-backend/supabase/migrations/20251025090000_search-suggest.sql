@@ -0,0 +1,13 @@
+-- ================================
+-- Search autocomplete
+-- ================================
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- word-prefix matching on titles ("photo" -> "Lens Photography")
+CREATE INDEX IF NOT EXISTS idx_vendors_title_simple_tsv ON vendors USING GIN (to_tsvector('simple', title));
+-- typo-tolerant matching
+CREATE INDEX IF NOT EXISTS idx_vendors_title_trgm ON vendors USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_vendors_city_trgm ON vendors USING GIN (city gin_trgm_ops);
+
+-- popularity = saves + bookings
+CREATE INDEX IF NOT EXISTS idx_saved_items_vendor_id ON saved_items(vendor_id);
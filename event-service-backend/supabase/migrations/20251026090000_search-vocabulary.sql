@@ -0,0 +1,18 @@
+-- ================================
+-- Fuzzy search vocabulary
+-- ================================
+-- Unstemmed words from listings, used for "did you mean" suggestions.
+-- Refreshed periodically by the background jobs (jobs.RefreshSearchVocabulary).
+CREATE MATERIALIZED VIEW IF NOT EXISTS search_vocabulary AS
+  SELECT word, ndoc
+  FROM ts_stat($$
+    SELECT to_tsvector('simple',
+      COALESCE(title, '') || ' ' || COALESCE(category, '') || ' ' ||
+      COALESCE(description, '') || ' ' || COALESCE(location, ''))
+    FROM vendors
+  $$)
+  WHERE length(word) > 2;
+
+-- unique index lets the view be refreshed CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_vocabulary_word ON search_vocabulary(word);
+CREATE INDEX IF NOT EXISTS idx_search_vocabulary_word_trgm ON search_vocabulary USING GIN (word gin_trgm_ops);
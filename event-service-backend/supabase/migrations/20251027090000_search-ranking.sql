@@ -0,0 +1,56 @@
+-- ================================
+-- Weighted search vector, synonyms and ranking settings
+-- ================================
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- Title hits outrank category, description and location hits
+CREATE OR REPLACE FUNCTION update_vendor_search_vector() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(NEW.category, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(NEW.description, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(NEW.location, '')), 'D');
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS vendor_search_vector_trigger ON vendors;
+CREATE TRIGGER vendor_search_vector_trigger
+BEFORE INSERT OR UPDATE ON vendors
+FOR EACH ROW
+EXECUTE FUNCTION update_vendor_search_vector();
+
+-- Rebuild existing vectors through the trigger
+UPDATE vendors SET title = title;
+
+CREATE INDEX IF NOT EXISTS idx_vendors_search_vector ON vendors USING GIN (search_vector);
+
+-- Query-time synonyms: a search containing term also matches each synonym
+-- in its place ("dj" -> "entertainment", "mehndi" -> "henna")
+CREATE TABLE IF NOT EXISTS search_synonyms (
+  id SERIAL PRIMARY KEY,
+  term TEXT UNIQUE NOT NULL,          -- stored lowercase
+  synonyms TEXT[] NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Single-row ranking configuration
+CREATE TABLE IF NOT EXISTS search_settings (
+  id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  weight_title REAL DEFAULT 1.0,        -- setweight 'A'
+  weight_category REAL DEFAULT 0.4,     -- 'B'
+  weight_description REAL DEFAULT 0.2,  -- 'C'
+  weight_location REAL DEFAULT 0.1,     -- 'D'
+  rating_boost REAL DEFAULT 0.5,        -- a 5.0 vendor ranks (1 + rating_boost)x higher
+  featured_boost REAL DEFAULT 0.25,     -- featured vendors rank (1 + featured_boost)x higher
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  -- ts_rank raises "weight out of range" above 1
+  CHECK (weight_title BETWEEN 0 AND 1),
+  CHECK (weight_category BETWEEN 0 AND 1),
+  CHECK (weight_description BETWEEN 0 AND 1),
+  CHECK (weight_location BETWEEN 0 AND 1)
+);
+
+INSERT INTO search_settings (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
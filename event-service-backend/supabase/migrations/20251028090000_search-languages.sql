@@ -0,0 +1,39 @@
+-- ================================
+-- Multi-language search
+-- ================================
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS language TEXT DEFAULT 'en';
+
+-- Text search config for a listing/query language code. Postgres has no
+-- stemmers for Hindi or the regional languages, so those use 'simple'.
+CREATE OR REPLACE FUNCTION vendor_search_config(lang TEXT) RETURNS regconfig
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT CASE lower(COALESCE(lang, 'en'))
+    WHEN 'en' THEN 'english'::regconfig
+    ELSE 'simple'::regconfig
+  END
+$$;
+
+-- Weighted vector in the listing's language, plus unstemmed 'simple' tokens
+-- so queries in another language or transliterations still match.
+CREATE OR REPLACE FUNCTION update_vendor_search_vector() RETURNS trigger AS $$
+DECLARE
+  cfg regconfig := vendor_search_config(NEW.language);
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector(cfg, coalesce(NEW.title, '')), 'A') ||
+    setweight(to_tsvector(cfg, coalesce(NEW.category, '')), 'B') ||
+    setweight(to_tsvector(cfg, coalesce(NEW.description, '')), 'C') ||
+    setweight(to_tsvector(cfg, coalesce(NEW.location, '')), 'D');
+  IF cfg <> 'simple'::regconfig THEN
+    NEW.search_vector := NEW.search_vector ||
+      setweight(to_tsvector('simple', coalesce(NEW.title, '')), 'A') ||
+      setweight(to_tsvector('simple', coalesce(NEW.category, '')), 'B') ||
+      setweight(to_tsvector('simple', coalesce(NEW.description, '')), 'C') ||
+      setweight(to_tsvector('simple', coalesce(NEW.location, '')), 'D');
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Rebuild existing vectors through the trigger
+UPDATE vendors SET title = title;
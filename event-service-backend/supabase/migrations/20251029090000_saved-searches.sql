@@ -0,0 +1,16 @@
+-- ================================
+-- Saved searches
+-- ================================
+CREATE TABLE IF NOT EXISTS saved_searches (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  query TEXT NOT NULL,               -- /search/vendors query string, e.g. q=dj&city=pune
+  notify BOOLEAN DEFAULT TRUE,       -- push when new vendors or deals match
+  last_run_at TIMESTAMPTZ,           -- last alert run; matches newer than this are "new"
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_notify ON saved_searches(id) WHERE notify;
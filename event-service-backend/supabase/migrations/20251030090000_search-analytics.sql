@@ -0,0 +1,28 @@
+-- ================================
+-- Search analytics
+-- ================================
+-- Raw logs are pruned after a retention period by a background job.
+CREATE TABLE IF NOT EXISTS search_logs (
+  id TEXT PRIMARY KEY,               -- returned to clients as search_id
+  query TEXT NOT NULL,
+  normalized_query TEXT NOT NULL,    -- lower-cased, whitespace collapsed; reports group on this
+  filters JSONB DEFAULT '{}',
+  result_count INT NOT NULL,
+  fuzzy BOOLEAN DEFAULT FALSE,
+  latency_ms INT NOT NULL,
+  user_hash TEXT,                    -- salted hash of the user or client, never the raw id
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_logs_created_at ON search_logs(created_at);
+CREATE INDEX IF NOT EXISTS idx_search_logs_normalized_query ON search_logs(normalized_query, created_at);
+
+CREATE TABLE IF NOT EXISTS search_clicks (
+  id BIGSERIAL PRIMARY KEY,
+  search_id TEXT NOT NULL REFERENCES search_logs(id) ON DELETE CASCADE,
+  vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+  position INT,                      -- 1-based rank in the results, when the client sends it
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_clicks_search_id ON search_clicks(search_id);
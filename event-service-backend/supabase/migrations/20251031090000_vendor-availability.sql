@@ -0,0 +1,44 @@
+-- ================================
+-- Vendor availability
+-- ================================
+-- The app has always written blocked ranges to vendor_bookings, while the
+-- initial schema named the table unavailable_dates. Settle on vendor_bookings.
+DO $$
+BEGIN
+  IF to_regclass('public.vendor_bookings') IS NULL AND to_regclass('public.unavailable_dates') IS NOT NULL THEN
+    ALTER TABLE unavailable_dates RENAME TO vendor_bookings;
+  END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_vendor_bookings_vendor_range ON vendor_bookings(vendor_id, booked_from, booked_to);
+CREATE INDEX IF NOT EXISTS idx_bookings_vendor_confirmed ON bookings(vendor_id, event_date) WHERE status = 'confirmed';
+
+-- A vendor is available for the days p_from..p_to (inclusive) when no blocked
+-- range overlaps them and no confirmed booking falls on one of them.
+CREATE OR REPLACE FUNCTION vendor_is_available(p_vendor INT, p_from DATE, p_to DATE)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+  SELECT NOT EXISTS (
+           SELECT 1 FROM vendor_bookings vb
+           WHERE vb.vendor_id = p_vendor
+             AND vb.booked_from < (p_to + 1)::timestamptz
+             AND vb.booked_to > p_from::timestamptz
+         )
+     AND NOT EXISTS (
+           SELECT 1 FROM bookings b
+           WHERE b.vendor_id = p_vendor
+             AND b.status = 'confirmed'
+             AND b.event_date BETWEEN p_from AND p_to
+         )
+$$;
+
+-- First free day on or after p_from within a year, or NULL.
+CREATE OR REPLACE FUNCTION vendor_next_available_date(p_vendor INT, p_from DATE)
+RETURNS DATE
+LANGUAGE sql STABLE AS $$
+  SELECT d::date
+  FROM generate_series(p_from, p_from + 365, INTERVAL '1 day') AS d
+  WHERE vendor_is_available(p_vendor, d::date, d::date)
+  ORDER BY d
+  LIMIT 1
+$$;
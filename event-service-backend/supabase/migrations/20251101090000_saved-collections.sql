@@ -0,0 +1,21 @@
+-- ================================
+-- Saved vendor collections
+-- ================================
+-- Saved items without a collection make up the user's default collection,
+-- which is what GET /saved-vendors/me lists.
+CREATE TABLE IF NOT EXISTS saved_collections (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_collections_user_name ON saved_collections(user_id, lower(name));
+
+ALTER TABLE saved_items ADD COLUMN IF NOT EXISTS collection_id INT REFERENCES saved_collections(id) ON DELETE SET NULL;
+ALTER TABLE saved_items ADD COLUMN IF NOT EXISTS notes TEXT;
+ALTER TABLE saved_items ADD COLUMN IF NOT EXISTS tags TEXT[] DEFAULT '{}';
+ALTER TABLE saved_items ADD COLUMN IF NOT EXISTS position INT;   -- set when the user reorders; NULL sorts first
+
+CREATE INDEX IF NOT EXISTS idx_saved_items_collection ON saved_items(user_id, collection_id);
@@ -0,0 +1,48 @@
+-- ================================
+-- Shared saved-vendor collections (planning boards)
+-- ================================
+-- Items in a shared collection stay owned by the collection's owner;
+-- added_by records which collaborator put them there.
+ALTER TABLE saved_items ADD COLUMN IF NOT EXISTS added_by INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS saved_collection_members (
+  collection_id INT REFERENCES saved_collections(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE CASCADE,
+  role TEXT NOT NULL CHECK (role IN ('viewer', 'editor')),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (collection_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_collection_members_user ON saved_collection_members(user_id);
+
+CREATE TABLE IF NOT EXISTS saved_collection_invites (
+  id SERIAL PRIMARY KEY,
+  collection_id INT NOT NULL REFERENCES saved_collections(id) ON DELETE CASCADE,
+  token TEXT UNIQUE NOT NULL,
+  email TEXT,                        -- when set, only the user with this email may accept
+  role TEXT NOT NULL CHECK (role IN ('viewer', 'editor')),
+  invited_by INT REFERENCES users(id) ON DELETE SET NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  accepted_by INT REFERENCES users(id) ON DELETE SET NULL,
+  accepted_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_collection_invites_collection ON saved_collection_invites(collection_id);
+
+CREATE TABLE IF NOT EXISTS saved_item_comments (
+  id SERIAL PRIMARY KEY,
+  saved_item_id INT NOT NULL REFERENCES saved_items(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE SET NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_item_comments_item ON saved_item_comments(saved_item_id, created_at);
+
+CREATE TABLE IF NOT EXISTS saved_item_votes (
+  saved_item_id INT REFERENCES saved_items(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (saved_item_id, user_id)
+);
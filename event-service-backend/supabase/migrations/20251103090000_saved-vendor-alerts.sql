@@ -0,0 +1,41 @@
+-- ================================
+-- Price-drop and new-deal alerts for saved vendors
+-- ================================
+-- Opt-in per user; no row means no alerts
+CREATE TABLE IF NOT EXISTS saved_vendor_alerts (
+  user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  new_deals BOOLEAN NOT NULL DEFAULT TRUE,
+  price_drops BOOLEAN NOT NULL DEFAULT TRUE,
+  last_digest_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- changes after this go in the next digest
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Every change to a vendor's starting price, so the digest can tell a drop
+-- from a raise that was later reverted
+CREATE TABLE IF NOT EXISTS vendor_price_changes (
+  id SERIAL PRIMARY KEY,
+  vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+  old_price_min NUMERIC,
+  new_price_min NUMERIC,
+  changed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_price_changes_vendor ON vendor_price_changes(vendor_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_vendor_deals_created_at ON vendor_deals(created_at);
+
+-- price_min is generated from price_range, so it is up to date in an AFTER trigger
+CREATE OR REPLACE FUNCTION record_vendor_price_change() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO vendor_price_changes (vendor_id, old_price_min, new_price_min)
+  VALUES (NEW.id, OLD.price_min, NEW.price_min);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS vendor_price_change_trigger ON vendors;
+CREATE TRIGGER vendor_price_change_trigger
+AFTER UPDATE OF price_range ON vendors
+FOR EACH ROW
+WHEN (OLD.price_min IS DISTINCT FROM NEW.price_min)
+EXECUTE FUNCTION record_vendor_price_change();
@@ -0,0 +1,24 @@
+-- ================================
+-- Events: what a user is planning, with its bookings and shortlist
+-- ================================
+CREATE TABLE IF NOT EXISTS events (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL CHECK (type IN ('wedding','engagement','birthday','anniversary','corporate','party','other')),
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL,
+  city TEXT,
+  guest_count INT CHECK (guest_count >= 0),
+  budget NUMERIC(12,2) CHECK (budget >= 0),
+  collection_id INT REFERENCES saved_collections(id) ON DELETE SET NULL,  -- the event's shortlist
+  status TEXT NOT NULL DEFAULT 'planning' CHECK (status IN ('planning','completed','cancelled')),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_user_start ON events(user_id, start_date);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS event_id INT REFERENCES events(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_event_id ON bookings(event_id);
@@ -0,0 +1,24 @@
+-- ================================
+-- Event budgets: planned spend per category and manual expenses
+-- ================================
+-- category is a vendor category, or 'other' for anything else
+CREATE TABLE IF NOT EXISTS event_budgets (
+  event_id INT REFERENCES events(id) ON DELETE CASCADE,
+  category TEXT NOT NULL,
+  amount NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (event_id, category)
+);
+
+-- Spend that did not go through a booking (deposits paid elsewhere, outfits...)
+CREATE TABLE IF NOT EXISTS event_expenses (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  category TEXT NOT NULL,
+  description TEXT NOT NULL,
+  amount NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+  spent_on DATE NOT NULL DEFAULT CURRENT_DATE,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_expenses_event ON event_expenses(event_id, spent_on);
@@ -0,0 +1,25 @@
+-- ================================
+-- Event guest lists and RSVPs
+-- ================================
+CREATE TABLE IF NOT EXISTS event_guests (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  email TEXT,
+  phone TEXT,
+  group_name TEXT,                       -- free-form: family, friends, office...
+  plus_ones_allowed INT NOT NULL DEFAULT 0 CHECK (plus_ones_allowed >= 0),
+  rsvp_token TEXT UNIQUE NOT NULL,       -- the guest's link to the public RSVP page
+  rsvp_status TEXT NOT NULL DEFAULT 'pending' CHECK (rsvp_status IN ('pending','attending','declined')),
+  plus_ones INT NOT NULL DEFAULT 0 CHECK (plus_ones >= 0),
+  meal_preference TEXT CHECK (meal_preference IN ('veg','non_veg','vegan','jain','other')),
+  rsvp_note TEXT,
+  invited_at TIMESTAMPTZ,
+  responded_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_guests_event ON event_guests(event_id, lower(name));
+-- one entry per email and event, so importing the same CSV twice is harmless
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_guests_event_email ON event_guests(event_id, lower(email)) WHERE email IS NOT NULL;
@@ -0,0 +1,92 @@
+-- ================================
+-- Planning checklists
+-- ================================
+-- Template tasks per event type, due offset_days before the event starts.
+-- A task with a category is ticked off when a booking in that vendor
+-- category is confirmed for the event.
+CREATE TABLE IF NOT EXISTS checklist_templates (
+  id SERIAL PRIMARY KEY,
+  event_type TEXT NOT NULL,
+  title TEXT NOT NULL,
+  category TEXT,
+  offset_days INT NOT NULL CHECK (offset_days >= 0),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (event_type, title)
+);
+
+CREATE TABLE IF NOT EXISTS event_tasks (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  template_id INT REFERENCES checklist_templates(id) ON DELETE SET NULL,
+  title TEXT NOT NULL,
+  category TEXT,
+  due_date DATE NOT NULL,
+  done BOOLEAN NOT NULL DEFAULT FALSE,
+  done_at TIMESTAMPTZ,
+  booking_id INT REFERENCES bookings(id) ON DELETE SET NULL,  -- the booking that completed it
+  reminded_at TIMESTAMPTZ,                                   -- last overdue reminder
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (event_id, template_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_tasks_event ON event_tasks(event_id, due_date);
+CREATE INDEX IF NOT EXISTS idx_event_tasks_overdue ON event_tasks(due_date) WHERE NOT done;
+
+CREATE OR REPLACE FUNCTION complete_event_tasks_for_booking() RETURNS trigger AS $$
+BEGIN
+  UPDATE event_tasks
+  SET done = TRUE, done_at = NOW(), booking_id = NEW.id, updated_at = NOW()
+  WHERE event_id = NEW.event_id AND NOT done
+    AND category = (SELECT category FROM vendors WHERE id = NEW.vendor_id);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS booking_completes_event_tasks ON bookings;
+CREATE TRIGGER booking_completes_event_tasks
+AFTER INSERT OR UPDATE OF status, event_id ON bookings
+FOR EACH ROW
+WHEN (NEW.status = 'confirmed' AND NEW.event_id IS NOT NULL)
+EXECUTE FUNCTION complete_event_tasks_for_booking();
+
+INSERT INTO checklist_templates (event_type, title, category, offset_days)
+VALUES
+  ('wedding', 'Book the venue', 'venue', 180),
+  ('wedding', 'Book a photographer', 'photography', 150),
+  ('wedding', 'Book the caterer', 'catering', 120),
+  ('wedding', 'Book decor', 'decor', 90),
+  ('wedding', 'Send invitations', NULL, 60),
+  ('wedding', 'Book entertainment', 'entertainment', 60),
+  ('wedding', 'Book the florist', 'florist', 45),
+  ('wedding', 'Give the caterer the final headcount', NULL, 14),
+  ('wedding', 'Confirm timings with every vendor', NULL, 7),
+  ('engagement', 'Book the venue', 'venue', 60),
+  ('engagement', 'Book the caterer', 'catering', 45),
+  ('engagement', 'Book a photographer', 'photography', 30),
+  ('engagement', 'Send invitations', NULL, 30),
+  ('engagement', 'Give the caterer the final headcount', NULL, 7),
+  ('birthday', 'Book the venue', 'venue', 30),
+  ('birthday', 'Send invitations', NULL, 21),
+  ('birthday', 'Book the caterer', 'catering', 21),
+  ('birthday', 'Book decor', 'decor', 14),
+  ('birthday', 'Give the caterer the final headcount', NULL, 5),
+  ('anniversary', 'Book the venue', 'venue', 45),
+  ('anniversary', 'Book the caterer', 'catering', 30),
+  ('anniversary', 'Send invitations', NULL, 30),
+  ('anniversary', 'Give the caterer the final headcount', NULL, 7),
+  ('corporate', 'Book the venue', 'venue', 90),
+  ('corporate', 'Send invitations', NULL, 45),
+  ('corporate', 'Book the caterer', 'catering', 45),
+  ('corporate', 'Book a photographer', 'photography', 30),
+  ('corporate', 'Give the caterer the final headcount', NULL, 7),
+  ('party', 'Book the venue', 'venue', 30),
+  ('party', 'Send invitations', NULL, 21),
+  ('party', 'Book the caterer', 'catering', 21),
+  ('party', 'Book entertainment', 'entertainment', 14),
+  ('party', 'Give the caterer the final headcount', NULL, 5),
+  ('other', 'Book the venue', 'venue', 60),
+  ('other', 'Book the caterer', 'catering', 30),
+  ('other', 'Send invitations', NULL, 30),
+  ('other', 'Give the caterer the final headcount', NULL, 7)
+ON CONFLICT (event_type, title) DO NOTHING;
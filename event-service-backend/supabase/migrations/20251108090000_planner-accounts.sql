@@ -0,0 +1,50 @@
+-- ================================
+-- Planner accounts and audit trail
+-- ================================
+-- users.role gains 'planner'. A planner works for a client once the client
+-- accepts; with requires_approval the client okays bookings and saves first.
+CREATE TABLE IF NOT EXISTS planner_clients (
+  planner_id INT REFERENCES users(id) ON DELETE CASCADE,
+  client_id INT REFERENCES users(id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','active')),
+  requires_approval BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  accepted_at TIMESTAMPTZ,
+  PRIMARY KEY (planner_id, client_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_planner_clients_client ON planner_clients(client_id);
+
+-- The planner managing an event; the client stays its owner
+ALTER TABLE events ADD COLUMN IF NOT EXISTS planner_id INT REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_events_planner ON events(planner_id, start_date);
+
+-- Actions a planner asked to take for a client, waiting for the client
+CREATE TABLE IF NOT EXISTS planner_requests (
+  id SERIAL PRIMARY KEY,
+  planner_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  client_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_id INT REFERENCES events(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('booking','save_vendor')),
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','approved','rejected')),
+  result_id INT,                     -- the booking or saved item created on approval
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  decided_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_planner_requests_client ON planner_requests(client_id, status);
+
+-- Who did what to whose event
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+  client_id INT REFERENCES users(id) ON DELETE CASCADE,  -- whose data it was
+  event_id INT REFERENCES events(id) ON DELETE SET NULL,
+  action TEXT NOT NULL,
+  details JSONB,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_event ON audit_log(event_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_client ON audit_log(client_id, created_at DESC);
@@ -0,0 +1,59 @@
+-- ================================
+-- Messaging between customers and vendors
+-- ================================
+-- One thread per customer and vendor listing, or per booking when the talk
+-- is about one.
+CREATE TABLE IF NOT EXISTS conversations (
+  id SERIAL PRIMARY KEY,
+  vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,   -- the customer
+  booking_id INT REFERENCES bookings(id) ON DELETE SET NULL,
+  last_message_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversations_thread
+  ON conversations(vendor_id, user_id, COALESCE(booking_id, 0));
+
+-- The customer and the user owning the listing. unread_count is kept in step
+-- with messages by the insert trigger below and reset on read.
+CREATE TABLE IF NOT EXISTS conversation_participants (
+  conversation_id INT REFERENCES conversations(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE CASCADE,
+  role TEXT NOT NULL CHECK (role IN ('customer','vendor')),
+  unread_count INT NOT NULL DEFAULT 0,
+  last_read_message_id INT NOT NULL DEFAULT 0,
+  last_read_at TIMESTAMPTZ,
+  PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_participants_user ON conversation_participants(user_id, role);
+
+CREATE TABLE IF NOT EXISTS messages (
+  id SERIAL PRIMARY KEY,
+  conversation_id INT NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+  sender_id INT REFERENCES users(id) ON DELETE SET NULL,
+  body TEXT NOT NULL DEFAULT '',
+  attachments JSONB NOT NULL DEFAULT '[]',  -- [{ "url", "name", "content_type", "size" }]
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_conversation ON messages(conversation_id, id DESC);
+
+CREATE OR REPLACE FUNCTION messages_touch_conversation() RETURNS trigger AS $$
+BEGIN
+  UPDATE conversations SET last_message_at = NEW.created_at WHERE id = NEW.conversation_id;
+  UPDATE conversation_participants SET unread_count = unread_count + 1
+  WHERE conversation_id = NEW.conversation_id AND user_id IS DISTINCT FROM NEW.sender_id;
+  -- the sender has read everything up to their own message
+  UPDATE conversation_participants
+  SET unread_count = 0, last_read_message_id = NEW.id, last_read_at = NEW.created_at
+  WHERE conversation_id = NEW.conversation_id AND user_id = NEW.sender_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS messages_touch_conversation ON messages;
+CREATE TRIGGER messages_touch_conversation
+  AFTER INSERT ON messages
+  FOR EACH ROW EXECUTE FUNCTION messages_touch_conversation();
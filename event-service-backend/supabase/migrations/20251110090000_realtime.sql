@@ -0,0 +1,92 @@
+-- ================================
+-- Realtime notifications
+-- ================================
+-- The API LISTENs on the 'realtime' channel and forwards each payload to
+-- the listed users' open streams. Payloads carry ids and small fields only;
+-- the app fetches the rest.
+
+-- New bookings and status changes go to the customer and the vendor
+CREATE OR REPLACE FUNCTION notify_booking_change() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'UPDATE' AND NEW.status IS NOT DISTINCT FROM OLD.status THEN
+    RETURN NEW;
+  END IF;
+  PERFORM pg_notify('realtime', json_build_object(
+    'user_ids', ARRAY(
+      SELECT DISTINCT x FROM unnest(ARRAY[NEW.user_id, (SELECT vendor_id FROM vendors WHERE id = NEW.vendor_id)]) x
+      WHERE x IS NOT NULL
+    ),
+    'type', CASE WHEN TG_OP = 'INSERT' THEN 'booking_created' ELSE 'booking_status' END,
+    'data', json_build_object(
+      'booking_id', NEW.id,
+      'vendor_id', NEW.vendor_id,
+      'event_id', NEW.event_id,
+      'status', NEW.status,
+      'previous_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END
+    )
+  )::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS booking_realtime ON bookings;
+CREATE TRIGGER booking_realtime
+AFTER INSERT OR UPDATE OF status ON bookings
+FOR EACH ROW
+EXECUTE FUNCTION notify_booking_change();
+
+-- New messages go to every participant, the sender included so their other
+-- devices catch up
+CREATE OR REPLACE FUNCTION notify_new_message() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('realtime', json_build_object(
+    'user_ids', ARRAY(SELECT user_id FROM conversation_participants WHERE conversation_id = NEW.conversation_id),
+    'type', 'message',
+    'data', json_build_object(
+      'conversation_id', NEW.conversation_id,
+      'message_id', NEW.id,
+      'sender_id', NEW.sender_id,
+      'preview', LEFT(NEW.body, 200),
+      'attachments', jsonb_array_length(NEW.attachments),
+      'created_at', NEW.created_at
+    )
+  )::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS message_realtime ON messages;
+CREATE TRIGGER message_realtime
+AFTER INSERT ON messages
+FOR EACH ROW
+EXECUTE FUNCTION notify_new_message();
+
+-- Read receipts. Sending a message moves the sender's own read mark too;
+-- that is only news when it cleared messages from the other side.
+CREATE OR REPLACE FUNCTION notify_messages_read() RETURNS trigger AS $$
+BEGIN
+  IF OLD.unread_count = 0 AND EXISTS (
+    SELECT 1 FROM messages WHERE id = NEW.last_read_message_id AND sender_id = NEW.user_id
+  ) THEN
+    RETURN NEW;
+  END IF;
+  PERFORM pg_notify('realtime', json_build_object(
+    'user_ids', ARRAY(SELECT user_id FROM conversation_participants WHERE conversation_id = NEW.conversation_id),
+    'type', 'messages_read',
+    'data', json_build_object(
+      'conversation_id', NEW.conversation_id,
+      'user_id', NEW.user_id,
+      'last_read_message_id', NEW.last_read_message_id,
+      'unread_count', NEW.unread_count
+    )
+  )::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS messages_read_realtime ON conversation_participants;
+CREATE TRIGGER messages_read_realtime
+AFTER UPDATE OF last_read_message_id ON conversation_participants
+FOR EACH ROW
+WHEN (NEW.last_read_message_id > OLD.last_read_message_id)
+EXECUTE FUNCTION notify_messages_read();
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+)
+
+// RecordAudit adds an entry to the audit trail. actorID did action on data
+// belonging to clientID; eventID may be nil. Failures are only logged: the
+// action itself already happened.
+func RecordAudit(actorID, clientID int, eventID *int, action string, details map[string]any) {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		raw = []byte("{}")
+	}
+	if _, err := config.DB.Exec(`
+		INSERT INTO audit_log (actor_id, client_id, event_id, action, details) VALUES ($1, $2, $3, $4, $5)
+	`, actorID, clientID, eventID, action, string(raw)); err != nil {
+		log.Printf("RecordAudit %q by user %d failed: %v", action, actorID, err)
+	}
+}
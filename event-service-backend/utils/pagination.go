@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Sort keys clients may pass as ?sort=. Each list endpoint supports the
+// subset that makes sense for it.
+const (
+	SortNewest    = "newest"
+	SortRating    = "rating"
+	SortPrice     = "price"
+	SortDistance  = "distance"
+	SortRelevance = "relevance"
+)
+
+// SortKey says how to order a list for one ?sort= value. Expr is the SQL
+// expression being sorted on; it must not be NULL (COALESCE it) so keyset
+// comparisons work. Cast is the type the cursor value is cast back to.
+type SortKey struct {
+	Expr string
+	Desc bool
+	Cast string // e.g. "timestamptz", "float8"
+}
+
+// Page is a parsed ?limit=&sort=&cursor= request for one list endpoint.
+//
+// Queries use it in three places: SortValue() as an extra selected column,
+// Where() in the WHERE clause and OrderBy() + LIMIT FetchLimit() at the end.
+// Each scanned row is passed to Track, then Done says how many rows belong on
+// the page and gives the next cursor.
+type Page struct {
+	Limit    int
+	Sort     string
+	key      SortKey
+	idColumn string
+	cursor   *pageCursor
+
+	seen int
+	last pageCursor
+}
+
+// pageCursor is what the opaque cursor token decodes to: the sort value and
+// id of the last row on the previous page.
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int    `json:"i"`
+}
+
+// ParsePage reads limit, sort and cursor from the query string. sorts lists
+// the keys this endpoint allows; idColumn is the unique tie-breaker column.
+func ParsePage(c *gin.Context, sorts map[string]SortKey, defaultSort, idColumn string) (Page, error) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+			return Page{Limit: DefaultPageLimit, Sort: defaultSort}, errors.New("limit must be a positive number")
+		}
+	}
+	return NewPage(limit, c.Query("sort"), c.Query("cursor"), sorts, defaultSort, idColumn)
+}
+
+// NewPage is ParsePage for callers that already have the values, e.g. a
+// search index. limit 0 means the default; sort "" means defaultSort.
+func NewPage(limit int, sort, cursor string, sorts map[string]SortKey, defaultSort, idColumn string) (Page, error) {
+	p := Page{Limit: DefaultPageLimit, Sort: defaultSort, idColumn: idColumn}
+
+	if limit > 0 {
+		p.Limit = min(limit, MaxPageLimit)
+	}
+
+	if s := strings.TrimSpace(sort); s != "" {
+		p.Sort = s
+	}
+	key, ok := sorts[p.Sort]
+	if !ok {
+		allowed := slices.Sorted(maps.Keys(sorts))
+		return p, fmt.Errorf("sort must be one of %s", strings.Join(allowed, ", "))
+	}
+	p.key = key
+
+	if cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		var cur pageCursor
+		if err != nil || json.Unmarshal(raw, &cur) != nil {
+			return p, errors.New("invalid cursor")
+		}
+		if cur.Sort != p.Sort {
+			return p, errors.New("cursor was issued for a different sort")
+		}
+		p.cursor = &cur
+	}
+
+	return p, nil
+}
+
+// After returns the sort value and id of the last row of the previous page,
+// for callers that filter rows themselves instead of using Where.
+func (p *Page) After() (value string, id int, ok bool) {
+	if p.cursor == nil {
+		return "", 0, false
+	}
+	return p.cursor.Value, p.cursor.ID, true
+}
+
+// Desc reports whether the page is sorted in descending order.
+func (p *Page) Desc() bool {
+	return p.key.Desc
+}
+
+// SortValue is the column to select; pass its value to Track.
+func (p *Page) SortValue() string {
+	return "(" + p.key.Expr + ")::text"
+}
+
+// Where returns the keyset condition for rows after the cursor, binding its
+// values through arg (which returns the placeholder, e.g. "$3").
+func (p *Page) Where(arg func(any) string) string {
+	if p.cursor == nil {
+		return "TRUE"
+	}
+	op := ">"
+	if p.key.Desc {
+		op = "<"
+	}
+	return fmt.Sprintf("((%s), %s) %s (%s::%s, %s)",
+		p.key.Expr, p.idColumn, op, arg(p.cursor.Value), p.key.Cast, arg(p.cursor.ID))
+}
+
+func (p *Page) OrderBy() string {
+	dir := "ASC"
+	if p.key.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s %s", p.key.Expr, dir, p.idColumn, dir)
+}
+
+// FetchLimit asks for one row more than the page so we know if there is a next page.
+func (p *Page) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// Track records the sort value and id of a scanned row, in result order.
+func (p *Page) Track(sortValue string, id int) {
+	p.seen++
+	if p.seen == p.Limit {
+		p.last = pageCursor{Sort: p.Sort, Value: sortValue, ID: id}
+	}
+}
+
+// Done returns how many of the tracked rows belong on this page and the
+// cursor for the next one, or "" when this is the last page.
+func (p *Page) Done() (int, string) {
+	if p.seen <= p.Limit {
+		return p.seen, ""
+	}
+	raw, _ := json.Marshal(p.last)
+	return p.Limit, base64.RawURLEncoding.EncodeToString(raw)
+}
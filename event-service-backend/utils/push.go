@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"errors"
+	"log"
+
+	"github.com/dharmaseervi/event-service-backend/config"
+	"github.com/dharmaseervi/event-service-backend/realtime"
+	expo "github.com/oliveroneill/exponent-server-sdk-golang/sdk"
+)
+
+var (
+	ErrNoPushToken      = errors.New("no push token for this user")
+	ErrInvalidPushToken = errors.New("invalid Expo push token")
+)
+
+// SendPush sends an Expo push notification to the device registered for the
+// local user id via POST /notif/push-token.
+func SendPush(userID int, title, body, sound string, data map[string]string) (expo.PushResponse, error) {
+	var token string
+	if err := config.DB.QueryRow(`SELECT token FROM push_tokens WHERE user_id=$1`, userID).Scan(&token); err != nil {
+		return expo.PushResponse{}, ErrNoPushToken
+	}
+
+	pushToken, err := expo.NewExponentPushToken(token)
+	if err != nil {
+		return expo.PushResponse{}, ErrInvalidPushToken
+	}
+
+	client := expo.NewPushClient(nil)
+
+	msg := &expo.PushMessage{
+		To:       []expo.ExponentPushToken{pushToken},
+		Title:    title,
+		Body:     body,
+		Sound:    sound,
+		Data:     data,
+		Priority: expo.DefaultPriority,
+	}
+
+	resp, err := client.Publish(msg)
+	if err != nil {
+		return resp, err
+	}
+	return resp, resp.ValidateResponse()
+}
+
+// NotifyUser is the fire-and-forget form of SendPush used by background
+// notifications; users without a push token are skipped silently. The
+// notification also goes to the user's open realtime streams, so the app
+// can show it in its inbox without a push token.
+func NotifyUser(userID int, title, body string, data map[string]string) {
+	if err := realtime.Publish([]int{userID}, "notification", map[string]any{
+		"title": title,
+		"body":  body,
+		"data":  data,
+	}); err != nil {
+		log.Printf("Realtime notification to user %d failed: %v", userID, err)
+	}
+	if _, err := SendPush(userID, title, body, "default", data); err != nil && err != ErrNoPushToken {
+		log.Printf("Push to user %d failed: %v", userID, err)
+	}
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
 
 // RespondWithJSON sends a JSON response
 func RespondWithJSON(c *gin.Context, statusCode int, payload interface{}) {
@@ -17,3 +21,27 @@ func RespondWithError(c *gin.Context, statusCode int, message string) {
 		"message": message,
 	})
 }
+
+// RespondWithPage sends one page of a list endpoint. next_cursor is null on
+// the last page. extra adds endpoint-specific top-level fields.
+func RespondWithPage(c *gin.Context, payload interface{}, page *Page, nextCursor string, extra ...gin.H) {
+	var next interface{}
+	if nextCursor != "" {
+		next = nextCursor
+	}
+	body := gin.H{
+		"status": "success",
+		"data":   payload,
+		"pagination": gin.H{
+			"limit":       page.Limit,
+			"sort":        page.Sort,
+			"next_cursor": next,
+		},
+	}
+	for _, e := range extra {
+		for k, v := range e {
+			body[k] = v
+		}
+	}
+	c.JSON(http.StatusOK, body)
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"strings"
+)
+
+const maxSlugLength = 80
+
+// Slugify joins the parts into a lowercase, hyphen-separated ASCII slug,
+// e.g. ("Royal Palace Banquets", "Pune") -> "royal-palace-banquets-pune".
+// Characters outside a-z and 0-9 become separators.
+func Slugify(parts ...string) string {
+	var b strings.Builder
+	dash := false
+	for _, part := range parts {
+		for _, r := range strings.ToLower(part) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				if dash && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				b.WriteRune(r)
+				dash = false
+			} else {
+				dash = true
+			}
+		}
+		dash = true
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SQLArgs collects positional query arguments; Add returns the placeholder.
+type SQLArgs []any
+
+func (a *SQLArgs) Add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// ErrDistanceNeedsPosition is returned for sort=distance without lat and lng.
+var ErrDistanceNeedsPosition = errors.New("sort=distance needs numeric lat and lng")
+
+// VendorSorts returns the sort keys for lists of vendor listings. col
+// prefixes the vendors columns (e.g. "v."). The distance key binds lat and
+// lng and is only built when sort asks for it, so unused placeholders never
+// reach the query.
+func VendorSorts(col, sort string, lat, lng *float64, args *SQLArgs) (map[string]SortKey, error) {
+	sorts := map[string]SortKey{
+		SortNewest: {Expr: col + "created_at", Desc: true, Cast: "timestamptz"},
+		SortRating: {Expr: "COALESCE(" + col + "rating, 0)::float8", Desc: true, Cast: "float8"},
+		// listings without a parsable price sort last
+		SortPrice:    {Expr: "COALESCE(" + col + "price_min, 1e15)::float8", Cast: "float8"},
+		SortDistance: {}, // filled in below when requested
+	}
+
+	if sort != SortDistance {
+		return sorts, nil
+	}
+	if lat == nil || lng == nil {
+		return nil, ErrDistanceNeedsPosition
+	}
+	sorts[SortDistance] = SortKey{
+		Expr: fmt.Sprintf("COALESCE(distance_km(%s, %s, %slatitude, %slongitude), 1e9)",
+			args.Add(*lat), args.Add(*lng), col, col),
+		Cast: "float8",
+	}
+	return sorts, nil
+}